@@ -6,4 +6,5 @@ require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/jarcoal/httpmock v1.0.6
 	golang.org/x/net v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -6,6 +6,7 @@ import (
 )
 
 type ApiHandler struct {
+	*api.AlarmMixin
 	*api.AuthMixin
 	*api.DeviceMixin
 	*api.DisplayMixin
@@ -27,6 +28,7 @@ func NewApiHandler(username string, password string, host string, restOpts ...re
 	handler := rest.NewRestHandler(host, restOpts...)
 
 	return &ApiHandler{
+		&api.AlarmMixin{},
 		&api.AuthMixin{
 			Username: username,
 			Password: password,
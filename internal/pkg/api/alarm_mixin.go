@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+)
+
+type AlarmMixin struct{}
+
+// Get the camera's motion detection alarm
+// See examples/response/GetAlarmMotion.json for example response data
+func (am *AlarmMixin) GetMotionAlarm() func(handler *rest.RestHandler) (*models.MotionAlarm, error) {
+	return func(handler *rest.RestHandler) (*models.MotionAlarm, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetAlarm",
+			"action": 1,
+			"param": map[string]interface{}{
+				"Alarm": map[string]interface{}{
+					"channel": 0,
+					"type":    "md",
+				},
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetAlarm")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var alarm *models.MotionAlarm
+
+		err = json.Unmarshal(result.Value["Alarm"], &alarm)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if alarm == nil {
+			return nil, fmt.Errorf("camera did not return its motion alarm")
+		}
+
+		return alarm, nil
+	}
+}
+
+// SetMotionAlarm Replace the camera's motion detection alarm, e.g. with one read through GetMotionAlarm
+func (am *AlarmMixin) SetMotionAlarm(alarm *models.MotionAlarm) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if alarm == nil {
+			return false, fmt.Errorf("motion alarm is required")
+		}
+
+		if len(alarm.Schedule.Table) != 168 {
+			return false, fmt.Errorf("motion alarm schedule needs 168 hours, got %d", len(alarm.Schedule.Table))
+		}
+
+		alarm.Type = "md"
+
+		payload := map[string]interface{}{
+			"cmd":    "SetAlarm",
+			"action": 0,
+			"param": map[string]interface{}{
+				"Alarm": alarm,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "SetAlarm")
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not set motion alarm. camera responded with %v", result.Value)
+	}
+}
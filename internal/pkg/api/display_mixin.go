@@ -89,7 +89,52 @@ func (dm *DisplayMixin) GetMask() func(handler *rest.RestHandler) (*models.MaskD
 	}
 }
 
+// SetMask Replace the camera's privacy masks, e.g. with the masks read through GetMask
+func (dm *DisplayMixin) SetMask(mask *models.MaskData) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if mask == nil {
+			return false, fmt.Errorf("mask is required")
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetMask",
+			"action": 0,
+			"param": map[string]interface{}{
+				"Mask": mask,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "SetMask")
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not set mask. camera responded with %v", result.Value)
+	}
+}
+
 // SetOSD Set the camera's on-screen display
+// Fields not covered by the osdOption arguments fall back to the defaults below, use UpdateOSD to keep the
+// camera's current values instead.
+// Defaults:
+// bgcolor: disabled
+// channel: 0
+// osdChannel: disabled, "Camera1", "Lower Right"
+// osdTime: disabled, "Top Center"
+// watermark: disabled
 func (dm *DisplayMixin) SetOSD(osdOption ...options.OsdOption) func(handler *rest.RestHandler) (bool,
 	error) {
 
@@ -117,20 +162,7 @@ func (dm *DisplayMixin) SetOSD(osdOption ...options.OsdOption) func(handler *res
 			"cmd":    "SetOsd",
 			"action": 1,
 			"param": map[string]interface{}{
-				"Osd": map[string]interface{}{
-					"bgcolor": osd.BgColor,
-					"channel": osd.Channel,
-					"osdChannel": map[string]interface{}{
-						"enable": osd.OsdChannel.Enable,
-						"name":   osd.OsdChannel.Name,
-						"pos":    osd.OsdChannel.Pos,
-					},
-					"osdTime": map[string]interface{}{
-						"enable": osd.OsdTime.Enable,
-						"pos":    osd.OsdTime.Pos,
-					},
-					"watermark": osd.Watermark,
-				},
+				"Osd": osd,
 			},
 		}
 
@@ -155,3 +187,18 @@ func (dm *DisplayMixin) SetOSD(osdOption ...options.OsdOption) func(handler *res
 		return false, fmt.Errorf("camera could not set osd. camera responded with %v", result.Value)
 	}
 }
+
+// UpdateOSD Read the camera's current on-screen display with GetOSD, apply the osdOption arguments and write it
+// back with SetOSD, so only the fields passed in are changed.
+func (dm *DisplayMixin) UpdateOSD(osdOption ...options.OsdOption) func(handler *rest.RestHandler) (bool,
+	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		osd, err := dm.GetOSD()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		return dm.SetOSD(append([]options.OsdOption{options.WithOsdOptionBase(osd)}, osdOption...)...)(handler)
+	}
+}
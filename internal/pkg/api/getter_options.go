@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+)
+
+type getterOptions struct {
+	action int
+}
+
+type OptionGetter func(*getterOptions)
+
+// Request the camera's initial (default) values and legal ranges alongside the current values.
+// Asking for them sends action 1, otherwise action 0 is sent and only the current values are returned.
+func GetterOptionDetailed(detailed bool) OptionGetter {
+	return func(g *getterOptions) {
+		if detailed {
+			g.action = 1
+		} else {
+			g.action = 0
+		}
+	}
+}
+
+// helper to build the getter options, detailed is the getter's default
+func newGetterOptions(detailed bool, opts []OptionGetter) *getterOptions {
+	g := &getterOptions{}
+
+	GetterOptionDetailed(detailed)(g)
+
+	for _, op := range opts {
+		op(g)
+	}
+
+	return g
+}
+
+// helper to decode the initial and range blocks of a response.
+// Blocks the camera did not send are left untouched.
+func unmarshalInitialAndRange(result *rest.GeneralData, key string, initial interface{}, rng interface{}) error {
+	if raw, ok := result.Initial[key]; ok {
+		if err := json.Unmarshal(raw, initial); err != nil {
+			return err
+		}
+	}
+
+	if raw, ok := result.Range[key]; ok {
+		if err := json.Unmarshal(raw, rng); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
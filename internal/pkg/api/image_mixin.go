@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
 	"net/url"
@@ -11,110 +12,146 @@ import (
 type ImageMixin struct {
 }
 
-type imageAdvanced struct {
-	antiFlicker  string
-	exposure     string
-	gainMin      int
-	gainMax      int
-	shutterMin   int
-	shutterMax   int
-	blueGain     int
-	redGain      int
-	whiteBalance string
-	dayNight     string
-	backLight    string
-	blc          int
-	drc          int
-	rotation     int
-	mirroring    int
-	nr3d         int
+type OptionAdvancedImageSetting func(*models.Isp)
+
+type OptionImageSetting func(*models.Image)
+
+// GetImageSettings Get the camera's image settings (brightness, contrast, hue, saturation and sharpness)
+// together with the initial values and range of legal values the camera reports for each of them.
+// Initial and Range are nil when the camera does not report them or GetterOptionDetailed(false) is passed.
+// See examples/response/GetImage.json for example response data
+func (im *ImageMixin) GetImageSettings(getterOptions ...OptionGetter) func(handler *rest.RestHandler) (
+	*models.ImageSettings, error) {
+	getter := newGetterOptions(true, getterOptions)
+
+	return func(handler *rest.RestHandler) (*models.ImageSettings, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetImage",
+			"action": getter.action,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetImage")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var imageData *models.Image
+
+		err = json.Unmarshal(result.Value["Image"], &imageData)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if imageData == nil {
+			return nil, fmt.Errorf("camera did not return its image settings")
+		}
+
+		var imageInitial *models.Image
+		var imageRange *models.ImageRange
+
+		err = unmarshalInitialAndRange(result, "Image", &imageInitial, &imageRange)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.ImageSettings{
+			Image:   imageData,
+			Initial: imageInitial,
+			Range:   imageRange,
+		}, nil
+	}
 }
 
-type image struct {
-	brightness int
-	contrast   int
-	hue        int
-	saturation int
-	sharpness  int
+// GetAdvanceImageSettings Get the camera's advanced image (ISP) settings such as exposure, day/night mode,
+// rotation and mirroring together with the initial values and range of legal values the camera reports for each
+// of them.
+// Use the models.Isp <field>Mode methods to map the values onto their enum types.
+// Initial and Range are nil when the camera does not report them or GetterOptionDetailed(false) is passed.
+// See examples/response/GetIsp.json for example response data
+func (im *ImageMixin) GetAdvanceImageSettings(getterOptions ...OptionGetter) func(handler *rest.RestHandler) (
+	*models.IspSettings, error) {
+	getter := newGetterOptions(true, getterOptions)
+
+	return func(handler *rest.RestHandler) (*models.IspSettings, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetIsp",
+			"action": getter.action,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetIsp")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var ispData *models.Isp
+
+		err = json.Unmarshal(result.Value["Isp"], &ispData)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ispData == nil {
+			return nil, fmt.Errorf("camera did not return its advanced image settings")
+		}
+
+		var ispInitial *models.Isp
+		var ispRange *models.IspRange
+
+		err = unmarshalInitialAndRange(result, "Isp", &ispInitial, &ispRange)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.IspSettings{
+			Isp:     ispData,
+			Initial: ispInitial,
+			Range:   ispRange,
+		}, nil
+	}
 }
 
-type OptionAdvancedImageSetting func(*imageAdvanced)
-
-type OptionImageSetting func(*image)
-
-// Set the Advanced Image setting. Parameters are optional and will fallback to defaults.
-// Defaults:
-// antiFlicker:  "Outdoor",
-// exposure:     "Auto",
-// gainMin:      1,
-// gainMax:      62,
-// shutterMin:   1,
-// shutterMax:   125,
-// blueGain:     128,
-// redGain:      128,
-// whiteBalance: "Auto",
-// dayNight:     "Auto",
-// backLight:    "DynamicRangeControl",
-// blc:          128,
-// drc:          128,
-// rotation:     0,
-// mirroring:    0,
-// nr3d:         1,
+// Set the Advanced Image setting.
+// The camera's current settings are read first and only the fields passed as options are changed.
+// The result is validated against the camera's range before it is sent, returning a *models.ValidationError
+// describing every offending field.
 func (im *ImageMixin) SetAdvanceImageSettings(imageAdvancedOptions ...OptionAdvancedImageSetting) func(handler *rest.RestHandler) (bool,
 	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		current, err := im.GetAdvanceImageSettings()(handler)
 
-	ias := &imageAdvanced{
-		antiFlicker:  "Outdoor",
-		exposure:     "Auto",
-		gainMin:      1,
-		gainMax:      62,
-		shutterMin:   1,
-		shutterMax:   125,
-		blueGain:     128,
-		redGain:      128,
-		whiteBalance: "Auto",
-		dayNight:     "Auto",
-		backLight:    "DynamicRangeControl",
-		blc:          128,
-		drc:          128,
-		rotation:     0,
-		mirroring:    0,
-		nr3d:         1,
-	}
+		if err != nil {
+			return false, err
+		}
 
-	for _, op := range imageAdvancedOptions {
-		op(ias)
-	}
+		ias := current.Isp
 
-	return func(handler *rest.RestHandler) (bool, error) {
+		for _, op := range imageAdvancedOptions {
+			op(ias)
+		}
+
+		if current.Range != nil {
+			if err := current.Range.Validate(ias); err != nil {
+				return false, err
+			}
+		}
 
 		payload := map[string]interface{}{
 			"cmd":    "SetIsp",
 			"action": 0,
 			"param": map[string]interface{}{
-				"Isp": map[string]interface{}{
-					"channel":     0,
-					"antiFlicker": ias.antiFlicker,
-					"exposure":    ias.exposure,
-					"gain": map[string]interface{}{
-						"min": ias.gainMin,
-						"max": ias.gainMax,
-					},
-					"shutter": map[string]interface{}{
-						"min": ias.shutterMin,
-						"max": ias.shutterMax,
-					},
-					"blueGain":     ias.blueGain,
-					"redGain":      ias.redGain,
-					"whiteBalance": ias.whiteBalance,
-					"dayNight":     ias.dayNight,
-					"backLight":    ias.backLight,
-					"blc":          ias.blc,
-					"drc":          ias.drc,
-					"rotation":     ias.rotation,
-					"mirroring":    ias.mirroring,
-					"nr3d":         ias.nr3d,
-				},
+				"Isp": ias,
 			},
 		}
 
@@ -140,41 +177,36 @@ func (im *ImageMixin) SetAdvanceImageSettings(imageAdvancedOptions ...OptionAdva
 	}
 }
 
-// Set the Image Settings. Parameters are optional and will fallback to defautls.
-// Defaults:
-// brightness: 128,
-// contrast:   62,
-// hue:        1,
-// saturation: 125,
-// sharpness:  128,
+// Set the Image Settings.
+// The camera's current settings are read first and only the fields passed as options are changed.
+// The result is validated against the camera's range before it is sent, returning a *models.ValidationError
+// describing every offending field.
 func (im *ImageMixin) SetImageSettings(imageOptions ...OptionImageSetting) func(handler *rest.RestHandler) (bool,
 	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		current, err := im.GetImageSettings()(handler)
 
-	img := &image{
-		brightness: 128,
-		contrast:   62,
-		hue:        1,
-		saturation: 125,
-		sharpness:  128,
-	}
+		if err != nil {
+			return false, err
+		}
 
-	for _, op := range imageOptions {
-		op(img)
-	}
+		img := current.Image
+
+		for _, op := range imageOptions {
+			op(img)
+		}
+
+		if current.Range != nil {
+			if err := current.Range.Validate(img); err != nil {
+				return false, err
+			}
+		}
 
-	return func(handler *rest.RestHandler) (bool, error) {
 		payload := map[string]interface{}{
 			"cmd":    "SetImage",
 			"action": 0,
 			"param": map[string]interface{}{
-				"Image": map[string]interface{}{
-					"bright":     img.brightness,
-					"channel":    0,
-					"contrast":   img.contrast,
-					"hue":        img.hue,
-					"saturation": img.saturation,
-					"sharpen":    img.sharpness,
-				},
+				"Image": img,
 			},
 		}
 
@@ -189,7 +221,7 @@ func (im *ImageMixin) SetImageSettings(imageOptions ...OptionImageSetting) func(
 		err = json.Unmarshal(result.Value["rspCode"], &respCode)
 
 		if err != nil {
-			return false, nil
+			return false, err
 		}
 
 		if respCode == 200 {
@@ -217,169 +249,168 @@ func (im *ImageMixin) Snap() func(handler *rest.RestHandler) ([]byte, error) {
 }
 
 // Set Image Brightness
-// Default: 128
 func ImageOptionBrightness(brightness int) OptionImageSetting {
-	return func(i *image) {
-		i.brightness = brightness
+	return func(i *models.Image) {
+		i.Brightness = brightness
 	}
 }
 
 // Set Image Contrast
-// Default: 62
 func ImageOptionContrast(contrast int) OptionImageSetting {
-	return func(i *image) {
-		i.contrast = contrast
+	return func(i *models.Image) {
+		i.Contrast = contrast
 	}
 }
 
 // Set Image Hue
-// Default: 1
 func ImageOptionHue(hue int) OptionImageSetting {
-	return func(i *image) {
-		i.hue = hue
+	return func(i *models.Image) {
+		i.Hue = hue
 	}
 }
 
 // Set Image Saturation
-// Default: 125
 func ImageOptionSaturation(saturation int) OptionImageSetting {
-	return func(i *image) {
-		i.saturation = saturation
+	return func(i *models.Image) {
+		i.Saturation = saturation
 	}
 }
 
 // Set Image Sharpness
-// Default: 128
 func ImageOptionSharpness(sharpness int) OptionImageSetting {
-	return func(i *image) {
-		i.sharpness = sharpness
+	return func(i *models.Image) {
+		i.Sharpness = sharpness
 	}
 }
 
 // Set the anti flicker value
-// Default: Outdoor
 func ImageAdvancedOptionAntiFlicker(antiFlicker enum.AntiFlicker) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.antiFlicker = antiFlicker.Value()
+	return func(advanced *models.Isp) {
+		advanced.AntiFlicker = antiFlicker.Value()
 	}
 }
 
 // Set the exposure value
-// Default: Auto
 func ImageAdvancedOptionExposure(exposure enum.Exposure) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.exposure = exposure.Value()
+	return func(advanced *models.Isp) {
+		advanced.Exposure = exposure.Value()
 	}
 }
 
 // Set the gain min value
-// Default: 1
 func ImageAdvancedOptionGainMin(gainMin int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.gainMin = gainMin
+	return func(advanced *models.Isp) {
+		advanced.Gain.Min = gainMin
 	}
 }
 
 // Set the gain max value
-// Default: 62
 func ImageAdvancedOptionGainMax(gainMax int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.gainMax = gainMax
+	return func(advanced *models.Isp) {
+		advanced.Gain.Max = gainMax
 	}
 }
 
 // Set the shutter min value
-// Default: 1
 func ImageAdvancedOptionShutterMin(shutterMin int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.shutterMin = shutterMin
+	return func(advanced *models.Isp) {
+		advanced.Shutter.Min = shutterMin
 	}
 }
 
 // Set the shutter max value
-// Default: 125
 func ImageAdvancedOptionShutterMax(shutterMax int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.shutterMax = shutterMax
+	return func(advanced *models.Isp) {
+		advanced.Shutter.Max = shutterMax
 	}
 }
 
 // Set the blue gain value
-// Default: 128
 func ImageAdvancedOptionBlueGain(blueGain int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.blueGain = blueGain
+	return func(advanced *models.Isp) {
+		advanced.BlueGain = blueGain
 	}
 }
 
 // Set the red gain value
-// Default: 128
 func ImageAdvancedOptionRedGain(redGain int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.redGain = redGain
+	return func(advanced *models.Isp) {
+		advanced.RedGain = redGain
 	}
 }
 
 // Set the white balance value
-// Default: Auto
 func ImageAdvancedOptionWhiteBalance(whiteBalance enum.WhiteBalance) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.whiteBalance = whiteBalance.Value()
+	return func(advanced *models.Isp) {
+		advanced.WhiteBalance = whiteBalance.Value()
 	}
 }
 
 // Set the day night value
-// Default: Auto
 func ImageAdvancedOptionDayNight(dayNight enum.DayNight) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.dayNight = dayNight.Value()
+	return func(advanced *models.Isp) {
+		advanced.DayNight = dayNight.Value()
 	}
 }
 
 // Set the backlight value
-// Default: DynamicRangeControl
 func ImageAdvancedOptionBacklight(backlight enum.Backlight) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.backLight = backlight.Value()
+	return func(advanced *models.Isp) {
+		advanced.BackLight = backlight.Value()
 	}
 }
 
 // Set the blc value
-// Default: 128
 func ImageAdvancedOptionBlc(blc int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.blc = blc
+	return func(advanced *models.Isp) {
+		advanced.Blc = blc
 	}
 }
 
 // Set the drc value
-// Default: 128
 func ImageAdvancedOptionDrc(drc int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.drc = drc
+	return func(advanced *models.Isp) {
+		advanced.Drc = drc
 	}
 }
 
 // Set the rotation value
-// Default: 0
 func ImageAdvancedOptionRotation(rotation enum.Rotation) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.rotation = rotation.Value()
+	return func(advanced *models.Isp) {
+		advanced.Rotation = rotation.Value()
 	}
 }
 
 // Set the mirroring value
-// Default: 0
 func ImageAdvancedOptionMirroring(mirroring int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.mirroring = mirroring
+	return func(advanced *models.Isp) {
+		advanced.Mirroring = mirroring
 	}
 }
 
 // Set the nr3d value
-// Default: 0
 func ImageAdvancedOptionNr3d(nr3d int) OptionAdvancedImageSetting {
-	return func(advanced *imageAdvanced) {
-		advanced.nr3d = nr3d
+	return func(advanced *models.Isp) {
+		advanced.Nr3d = nr3d
+	}
+}
+
+// Replace all the image settings with the ones given, typically a copy returned by GetImageSettings.
+// Must be passed before any other option as it replaces every field.
+func ImageOptionBase(base *models.Image) OptionImageSetting {
+	return func(i *models.Image) {
+		if base != nil {
+			*i = *base
+		}
+	}
+}
+
+// Replace all the advanced image settings with the ones given, typically a copy returned by GetAdvanceImageSettings.
+// Must be passed before any other option as it replaces every field.
+func ImageAdvancedOptionBase(base *models.Isp) OptionAdvancedImageSetting {
+	return func(advanced *models.Isp) {
+		if base != nil {
+			*advanced = *base
+		}
 	}
 }
@@ -2,65 +2,44 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
+	"golang.org/x/net/context"
+	"strings"
+	"time"
 )
 
 type NetworkMixin struct {
 }
 
 // SetNetworkPort Set the camera network ports using the NetworkPortOption<prop> functions
-// Defaults are automatically for the excluded networkPortOptions
-// http: 80
-// https: 443
-// media: 9000
-// onvif: 8000
-// rtmp: 1935
-// rtsp: 554
-// Only http & https ports are enabled by default
+// The camera's current ports are read first and only the fields passed as options are changed.
 func (nm *NetworkMixin) SetNetworkPort(networkPortOptions ...options.NetworkPortOption) func(handler *rest.RestHandler) (bool,
 	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		networkPorts, err := nm.GetNetworkPort()(handler)
 
-	// Defaults
-	networkPorts := &models.NetworkPort{
-		HttpEnable:  enum.Enabled,
-		HttpPort:    80,
-		HttpsEnable: enum.Enabled,
-		HttpsPort:   443,
-		MediaPort:   9000,
-		OnvifEnable: enum.Disabled,
-		OnvifPort:   8000,
-		RtmpEnable:  enum.Disabled,
-		RtmpPort:    1935,
-		RtspEnable:  enum.Disabled,
-		RtspPort:    554,
-	}
+		if err != nil {
+			return false, err
+		}
 
-	for _, op := range networkPortOptions {
-		op(networkPorts)
-	}
+		if networkPorts == nil {
+			return false, fmt.Errorf("camera did not return its network ports")
+		}
+
+		for _, op := range networkPortOptions {
+			op(networkPorts)
+		}
 
-	return func(handler *rest.RestHandler) (bool, error) {
 		payload := map[string]interface{}{
 			"cmd":    "SetNetPort",
 			"action": 0,
 			"param": map[string]interface{}{
-				"NetPort": map[string]interface{}{
-					"httpEnable":  networkPorts.HttpsEnable,
-					"httpPort":    networkPorts.HttpPort,
-					"httpsEnable": networkPorts.HttpsEnable,
-					"httpsPort":   networkPorts.HttpsPort,
-					"mediaPort":   networkPorts.MediaPort,
-					"onvifEnable": networkPorts.OnvifEnable,
-					"onvifPort":   networkPorts.OnvifPort,
-					"rtmpEnable":  networkPorts.RtmpEnable,
-					"rtmpPort":    networkPorts.RtmpPort,
-					"rtspEnable":  networkPorts.RtspEnable,
-					"rtspPort":    networkPorts.RtspPort,
-				},
+				"NetPort": networkPorts,
 			},
 		}
 
@@ -149,9 +128,9 @@ func (nm *NetworkMixin) GetWifi() func(handler *rest.RestHandler) (*models.Wifi,
 	}
 }
 
-// Scan the current camera's wifi network
-func (nm *NetworkMixin) ScanWifi() func(handler *rest.RestHandler) (*models.ScanWifi, error) {
-	return func(handler *rest.RestHandler) (*models.ScanWifi, error) {
+// Scan for the wireless networks the camera can see
+func (nm *NetworkMixin) ScanWifi() func(handler *rest.RestHandler) (models.ScanWifi, error) {
+	return func(handler *rest.RestHandler) (models.ScanWifi, error) {
 		payload := map[string]interface{}{
 			"cmd":    "ScanWifi",
 			"action": 1,
@@ -164,7 +143,7 @@ func (nm *NetworkMixin) ScanWifi() func(handler *rest.RestHandler) (*models.Scan
 			return nil, err
 		}
 
-		var scanWifi *models.ScanWifi
+		var scanWifi models.ScanWifi
 
 		err = json.Unmarshal(result.Value["ScanWifi"], &scanWifi)
 
@@ -176,31 +155,261 @@ func (nm *NetworkMixin) ScanWifi() func(handler *rest.RestHandler) (*models.Scan
 	}
 }
 
-// GetNetworkGeneral Get the camera's general network information
-func (nm *NetworkMixin) GetNetworkGeneral() func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
-	return func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+// GetWifiSignal Get the signal strength of the wireless network the camera is connected to
+func (nm *NetworkMixin) GetWifiSignal() func(handler *rest.RestHandler) (int, error) {
+	return func(handler *rest.RestHandler) (int, error) {
 		payload := map[string]interface{}{
-			"cmd":    "GetLocalLink",
+			"cmd":    "GetWifiSignal",
 			"action": 0,
-			"param":  map[string]interface{}{},
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetWifiSignal")
+
+		if err != nil {
+			return 0, err
 		}
 
-		resp, err := handler.Request("POST", payload, "GetLocalLink")
+		var signal int
+
+		err = json.Unmarshal(result.Value["wifiSignal"], &signal)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return signal, nil
+	}
+}
+
+// JoinWifi Make the camera join the wireless network and wait until it reports being connected.
+// The camera counts as connected once GetWifi returns the ssid and GetWifiSignal a signal. The camera is polled
+// every interval, errors while it switches networks are ignored, until it is connected or ctx is done.
+// The result holds the signal and the address of the camera's active link. When ctx is done first the result holds
+// what was last read and the error says why the join could not be confirmed.
+func (nm *NetworkMixin) JoinWifi(ctx context.Context, ssid string, password string, interval time.Duration) func(
+	handler *rest.RestHandler) (*models.WifiJoin, error) {
+	return func(handler *rest.RestHandler) (*models.WifiJoin, error) {
+		_, err := nm.SetWifi(ssid, password)(handler)
 
 		if err != nil {
 			return nil, err
 		}
 
-		var networkGeneral *models.NetworkGeneral
+		join := &models.WifiJoin{}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastErr error
+
+		for {
+			lastErr = readWifiJoin(nm, handler, ssid, join)
+
+			if lastErr == nil && join.Joined {
+				return join, nil
+			}
+
+			select {
+			case <-ctx.Done():
+				reason := fmt.Sprintf("camera reports ssid %q with signal %d", join.Ssid, join.Signal)
+
+				if lastErr != nil {
+					reason = lastErr.Error()
+				}
+
+				return join, fmt.Errorf("camera did not confirm joining %q (%s): %w", ssid, reason, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// helper to read the wifi state into join, Joined is set once the camera is connected to ssid
+func readWifiJoin(nm *NetworkMixin, handler *rest.RestHandler, ssid string, join *models.WifiJoin) error {
+	wifi, err := nm.GetWifi()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	if wifi != nil {
+		join.Ssid = wifi.Ssid
+	}
+
+	signal, err := nm.GetWifiSignal()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	join.Signal = signal
+
+	if join.Ssid != ssid || signal == 0 {
+		return nil
+	}
+
+	link, err := nm.GetNetworkGeneral()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	if link != nil {
+		join.ActiveLink = link.ActiveLink
+		join.Ip = link.Static.Ip
+	}
+
+	join.Joined = true
+
+	return nil
+}
+
+// GetNetworkGeneral Get the camera's general network information
+func (nm *NetworkMixin) GetNetworkGeneral() func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+	return func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+		return getNetworkGeneralWithContext(context.Background(), handler)
+	}
+}
+
+// SetLocalLink Switch the camera's wired network between DHCP and a static address and set its DNS servers using
+// the LocalLinkOption<prop> functions. The camera's current settings are read first and only the fields passed as
+// options are changed.
+// The camera moves to its new address straight away, see SetLocalLinkVerified to follow it there.
+func (nm *NetworkMixin) SetLocalLink(linkOptions ...options.LocalLinkOption) func(handler *rest.RestHandler) (bool,
+	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		link, err := nm.GetNetworkGeneral()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if link == nil {
+			return false, fmt.Errorf("camera did not return its local link")
+		}
 
-		err = json.Unmarshal(resp.Value["LocalLink"], &networkGeneral)
+		for _, op := range linkOptions {
+			op(link)
+		}
+
+		if err := link.Validate(); err != nil {
+			return false, err
+		}
+
+		return setNetworkSetting(handler, "SetLocalLink", "LocalLink", link, "local link")
+	}
+}
+
+// SetLocalLinkVerified Change the local link like SetLocalLink and make sure the camera can still be reached.
+// When the handler talks to the camera by its current address and that address changes, the handler is pointed at
+// the new one. The camera is then polled every interval until it answers or ctx is done, in which case the handler
+// is pointed back at the old address and an error describing both addresses is returned.
+// Switching from a static address the handler uses to DHCP is refused, as the new address cannot be known.
+// The link read back from the camera at its new address is returned.
+func (nm *NetworkMixin) SetLocalLinkVerified(ctx context.Context, interval time.Duration,
+	linkOptions ...options.LocalLinkOption) func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+	return func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+		current, err := nm.GetNetworkGeneral()(handler)
 
 		if err != nil {
 			return nil, err
 		}
 
-		return networkGeneral, nil
+		if current == nil {
+			return nil, fmt.Errorf("camera did not return its local link")
+		}
+
+		link := *current
+
+		for _, op := range linkOptions {
+			op(&link)
+		}
+
+		if err := link.Validate(); err != nil {
+			return nil, err
+		}
+
+		oldHost := handler.GetHost()
+		newHost := oldHost
+
+		// the static block holds the camera's current address, also while on DHCP, see models.NetworkGeneralStatic
+		if oldHost == current.Static.Ip {
+			if link.Type == enum.LINK_TYPE_DHCP.Value() && current.Type != link.Type {
+				return nil, fmt.Errorf("cannot verify switching %s to DHCP, its new address is unknown. "+
+					"use SetLocalLink and find the camera on the network instead", oldHost)
+			}
+
+			newHost = link.Static.Ip
+		}
+
+		_, err = setNetworkSetting(handler, "SetLocalLink", "LocalLink", &link, "local link")
+
+		if err != nil {
+			var cameraErr *rest.CameraError
+
+			// the camera may drop the connection while changing address, only a refusal is final
+			if errors.As(err, &cameraErr) || newHost == oldHost {
+				return nil, err
+			}
+		}
+
+		handler.SetHost(newHost)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			verified, err := getNetworkGeneralWithContext(ctx, handler)
+
+			if err == nil {
+				return verified, nil
+			}
+
+			var cameraErr *rest.CameraError
+
+			if errors.As(err, &cameraErr) {
+				return nil, fmt.Errorf("camera answered at its new address %s but could not read its local link: %w",
+					newHost, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				handler.SetHost(oldHost)
+
+				return nil, fmt.Errorf("camera did not answer at its new address %s (last error: %v), "+
+					"the handler points at its old address %s again: %w", newHost, err, oldHost, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// helper to read the local link, the request is aborted when ctx is done
+func getNetworkGeneralWithContext(ctx context.Context, handler *rest.RestHandler) (*models.NetworkGeneral, error) {
+	payload := map[string]interface{}{
+		"cmd":    "GetLocalLink",
+		"action": 0,
+		"param":  map[string]interface{}{},
 	}
+
+	resp, err := handler.RequestContext(ctx, "POST", payload, "GetLocalLink")
+
+	if err != nil {
+		return nil, err
+	}
+
+	var networkGeneral *models.NetworkGeneral
+
+	err = json.Unmarshal(resp.Value["LocalLink"], &networkGeneral)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return networkGeneral, nil
 }
 
 // GetNetworkPort Get the camera's network ports status and value
@@ -365,10 +574,404 @@ func (nm *NetworkMixin) GetNetworkPush() func(handler *rest.RestHandler) (*model
 	}
 }
 
-// Get the camera's network Status information is just a wrapper for networkGeneral
-// TODO: revise this, exactly copied from the reolink-python-api project.
-func (nm *NetworkMixin) GetNetworkStatus() func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
-	return func(handler *rest.RestHandler) (*models.NetworkGeneral, error) {
-		return nm.GetNetworkGeneral()(handler)
+// helper to send a network setting and check the camera accepted it, what names the setting in the error
+func setNetworkSetting(handler *rest.RestHandler, cmd string, key string, value interface{}, what string) (bool,
+	error) {
+	payload := map[string]interface{}{
+		"cmd":    cmd,
+		"action": 0,
+		"param": map[string]interface{}{
+			key: value,
+		},
+	}
+
+	result, err := handler.Request("POST", payload, cmd)
+
+	if err != nil {
+		return false, err
+	}
+
+	var respCode int
+
+	err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+	if err != nil {
+		return false, err
+	}
+
+	if respCode == 200 {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("camera could not set %s. camera responded with %v", what, result.Value)
+}
+
+// SetNtp Set the camera's NTP settings using the NtpOption<prop> functions
+// The camera's current settings are read first and only the fields passed as options are changed.
+func (nm *NetworkMixin) SetNtp(ntpOptions ...options.NtpOption) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		ntp, err := nm.GetNetworkNTP()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ntp == nil {
+			return false, fmt.Errorf("camera did not return its ntp settings")
+		}
+
+		for _, op := range ntpOptions {
+			op(ntp)
+		}
+
+		if ntp.Port < 1 || ntp.Port > 65535 {
+			return false, fmt.Errorf("invalid ntp port %d", ntp.Port)
+		}
+
+		return setNetworkSetting(handler, "SetNtp", "Ntp", ntp, "ntp")
+	}
+}
+
+// SetDdns Set the camera's DDNS settings using the DdnsOption<prop> functions
+// The camera's current settings are read first and only the fields passed as options are changed.
+func (nm *NetworkMixin) SetDdns(ddnsOptions ...options.DdnsOption) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		ddns, err := nm.GetNetworkDDNS()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ddns == nil {
+			return false, fmt.Errorf("camera did not return its ddns settings")
+		}
+
+		for _, op := range ddnsOptions {
+			op(ddns)
+		}
+
+		return setNetworkSetting(handler, "SetDdns", "Ddns", ddns, "ddns")
+	}
+}
+
+// SetEmail Set the camera's email settings using the EmailOption<prop> functions
+// The camera's current settings are read first and only the fields passed as options are changed.
+func (nm *NetworkMixin) SetEmail(emailOptions ...options.EmailOption) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		email, err := nm.GetNetworkEmail()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if email == nil {
+			return false, fmt.Errorf("camera did not return its email settings")
+		}
+
+		for _, op := range emailOptions {
+			op(email)
+		}
+
+		if email.SmtpPort < 1 || email.SmtpPort > 65535 {
+			return false, fmt.Errorf("invalid smtp port %d", email.SmtpPort)
+		}
+
+		if err := email.Schedule.Validate(); err != nil {
+			return false, err
+		}
+
+		return setNetworkSetting(handler, "SetEmail", "Email", email, "email")
+	}
+}
+
+// SetFtp Set the camera's FTP settings using the FtpOption<prop> functions
+// The camera's current settings are read first and only the fields passed as options are changed.
+func (nm *NetworkMixin) SetFtp(ftpOptions ...options.FtpOption) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		ftp, err := nm.GetNetworkFTP()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ftp == nil {
+			return false, fmt.Errorf("camera did not return its ftp settings")
+		}
+
+		for _, op := range ftpOptions {
+			op(ftp)
+		}
+
+		if ftp.Port < 1 || ftp.Port > 65535 {
+			return false, fmt.Errorf("invalid ftp port %d", ftp.Port)
+		}
+
+		if err := ftp.Schedule.Validate(); err != nil {
+			return false, err
+		}
+
+		return setNetworkSetting(handler, "SetFtp", "Ftp", ftp, "ftp")
+	}
+}
+
+// SetPush Set the camera's push notification settings using the PushOption<prop> functions
+// The camera's current settings are read first and only the fields passed as options are changed.
+func (nm *NetworkMixin) SetPush(pushOptions ...options.PushOption) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		push, err := nm.GetNetworkPush()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if push == nil {
+			return false, fmt.Errorf("camera did not return its push settings")
+		}
+
+		for _, op := range pushOptions {
+			op(push)
+		}
+
+		if err := push.Schedule.Validate(); err != nil {
+			return false, err
+		}
+
+		return setNetworkSetting(handler, "SetPush", "Push", push, "push")
+	}
+}
+
+// helper to ask the camera to deliver a test message with the given configuration.
+// A camera refusing or failing the delivery is a result, only failing to ask is an error.
+func testDelivery(handler *rest.RestHandler, cmd string, key string, value interface{}) (*models.DeliveryTest,
+	error) {
+	payload := map[string]interface{}{
+		"cmd":    cmd,
+		"action": 0,
+		"param": map[string]interface{}{
+			key: value,
+		},
+	}
+
+	result, err := handler.Request("POST", payload, cmd)
+
+	if err != nil {
+		var cameraErr *rest.CameraError
+
+		if errors.As(err, &cameraErr) {
+			return &models.DeliveryTest{
+				Success: false,
+				Detail:  cameraErr.Detail,
+				RspCode: cameraErr.RspCode,
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	var respCode int
+
+	err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if respCode != 200 {
+		return &models.DeliveryTest{
+			Success: false,
+			Detail:  fmt.Sprintf("camera responded with %v", result.Value),
+			RspCode: respCode,
+		}, nil
+	}
+
+	return &models.DeliveryTest{Success: true, RspCode: respCode}, nil
+}
+
+// TestEmail Make the camera send a test email.
+// Without options the stored configuration is tested, options describe a proposed configuration which is tested
+// without being saved, see options.WithEmailOptionBase to propose a complete one.
+func (nm *NetworkMixin) TestEmail(emailOptions ...options.EmailOption) func(handler *rest.RestHandler) (
+	*models.DeliveryTest, error) {
+	return func(handler *rest.RestHandler) (*models.DeliveryTest, error) {
+		email, err := nm.GetNetworkEmail()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if email == nil {
+			return nil, fmt.Errorf("camera did not return its email settings")
+		}
+
+		for _, op := range emailOptions {
+			op(email)
+		}
+
+		return testDelivery(handler, "TestEmail", "Email", email)
+	}
+}
+
+// TestFtp Make the camera upload a test file.
+// Without options the stored configuration is tested, options describe a proposed configuration which is tested
+// without being saved, see options.WithFtpOptionBase to propose a complete one.
+func (nm *NetworkMixin) TestFtp(ftpOptions ...options.FtpOption) func(handler *rest.RestHandler) (
+	*models.DeliveryTest, error) {
+	return func(handler *rest.RestHandler) (*models.DeliveryTest, error) {
+		ftp, err := nm.GetNetworkFTP()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ftp == nil {
+			return nil, fmt.Errorf("camera did not return its ftp settings")
+		}
+
+		for _, op := range ftpOptions {
+			op(ftp)
+		}
+
+		return testDelivery(handler, "TestFtp", "Ftp", ftp)
+	}
+}
+
+// GetUpnp Get whether the camera opens its ports on the router through UPnP
+func (nm *NetworkMixin) GetUpnp() func(handler *rest.RestHandler) (*models.NetworkUpnp, error) {
+	return func(handler *rest.RestHandler) (*models.NetworkUpnp, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetUpnp",
+			"action": 0,
+			"param":  map[string]interface{}{},
+		}
+
+		resp, err := handler.Request("POST", payload, "GetUpnp")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var networkUpnp *models.NetworkUpnp
+
+		err = json.Unmarshal(resp.Value["Upnp"], &networkUpnp)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return networkUpnp, nil
+	}
+}
+
+// SetUpnp Enable or disable UPnP
+func (nm *NetworkMixin) SetUpnp(enable enum.Toggle) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		networkUpnp, err := nm.GetUpnp()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if networkUpnp == nil {
+			return false, fmt.Errorf("camera did not return its upnp settings")
+		}
+
+		networkUpnp.Enable = enable
+
+		return setNetworkSetting(handler, "SetUpnp", "Upnp", networkUpnp, "upnp")
+	}
+}
+
+// GetP2p Get whether the camera can be reached through the Reolink cloud (P2P) and its UID
+func (nm *NetworkMixin) GetP2p() func(handler *rest.RestHandler) (*models.NetworkP2p, error) {
+	return func(handler *rest.RestHandler) (*models.NetworkP2p, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetP2p",
+			"action": 0,
+			"param":  map[string]interface{}{},
+		}
+
+		resp, err := handler.Request("POST", payload, "GetP2p")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var networkP2p *models.NetworkP2p
+
+		err = json.Unmarshal(resp.Value["P2p"], &networkP2p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return networkP2p, nil
+	}
+}
+
+// SetP2p Enable or disable access through the Reolink cloud (P2P), the UID is kept
+func (nm *NetworkMixin) SetP2p(enable enum.Toggle) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		networkP2p, err := nm.GetP2p()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if networkP2p == nil {
+			return false, fmt.Errorf("camera did not return its p2p settings")
+		}
+
+		networkP2p.Enable = enable
+
+		return setNetworkSetting(handler, "SetP2p", "P2p", networkP2p, "p2p")
+	}
+}
+
+// GetNetworkStatus Get the camera's runtime network state, combining the local link, the WiFi signal and the
+// throughput reported by GetPerformance
+func (nm *NetworkMixin) GetNetworkStatus() func(handler *rest.RestHandler) (*models.NetworkStatus, error) {
+	return func(handler *rest.RestHandler) (*models.NetworkStatus, error) {
+		link, err := nm.GetNetworkGeneral()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if link == nil {
+			return nil, fmt.Errorf("camera did not return its local link")
+		}
+
+		sm := &SystemMixin{}
+
+		performance, err := sm.GetPerformance()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		status := &models.NetworkStatus{
+			ActiveLink: link.ActiveLink,
+			Type:       link.Type,
+			Ip:         link.Static.Ip,
+			Mask:       link.Static.Mask,
+			Gateway:    link.Static.Gateway,
+			Dns1:       link.Dns.Dns1,
+			Dns2:       link.Dns.Dns2,
+			Mac:        link.Mac,
+		}
+
+		if performance != nil {
+			status.NetThroughput = performance.NetThroughput
+		}
+
+		if strings.EqualFold(link.ActiveLink, "WiFi") {
+			status.WifiSignal, err = nm.GetWifiSignal()(handler)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return status, nil
 	}
 }
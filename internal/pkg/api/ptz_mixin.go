@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+	"golang.org/x/net/context"
+	"sort"
+	"time"
 )
 
 type PtzMixin struct{}
@@ -20,20 +24,75 @@ type ptzPresetOptions struct {
 	Name  string
 }
 
+type ptzGuardOptions struct {
+	Enable       int
+	Timeout      int
+	SavePosition bool
+}
+
 type OptionPtzOperation func(*ptzOperationOptions)
 
+type OptionPtzGuard func(*ptzGuardOptions)
+
 type OptionPtzPreset func(*ptzPresetOptions)
 
+type ptzPresetSyncOptions struct {
+	DryRun         bool
+	RemoveUnlisted bool
+	Settle         time.Duration
+}
+
+type OptionPtzPresetSync func(*ptzPresetSyncOptions)
+
+type OptionPtzSerial func(*models.PtzSerial)
+
 // helper function for ptz presets
 func ptzPreset(enable bool, preset int, name string) interface{} {
+	enabled := 0
+
+	if enable {
+		enabled = 1
+	}
+
 	return map[string]interface{}{
 		"cmd":    "SetPtzPreset",
 		"action": 0,
 		"param": map[string]interface{}{
-			"channel": 0,
-			"enable":  enable,
-			"id":      preset,
-			"name":    name,
+			"PtzPreset": map[string]interface{}{
+				"channel": 0,
+				"enable":  enabled,
+				"id":      preset,
+				"name":    name,
+			},
+		},
+	}
+}
+
+// helper function for ptz guard commands
+// cmdStr "setPos" stores the settings (and the current position when savePosition is true), "toPos" moves the
+// camera to the stored guard position.
+func ptzGuard(cmdStr string, guard *ptzGuardOptions) interface{} {
+	ptzGuard := map[string]interface{}{
+		"channel": 0,
+		"cmdStr":  cmdStr,
+	}
+
+	if guard != nil {
+		ptzGuard["benable"] = guard.Enable
+		ptzGuard["timeout"] = guard.Timeout
+
+		if guard.SavePosition {
+			ptzGuard["bSaveCurrentPos"] = 1
+		} else {
+			ptzGuard["bSaveCurrentPos"] = 0
+		}
+	}
+
+	return map[string]interface{}{
+		"cmd":    "SetPtzGuard",
+		"action": 0,
+		"param": map[string]interface{}{
+			"PtzGuard": ptzGuard,
 		},
 	}
 }
@@ -46,8 +105,9 @@ func ptzOperation(ptzOperation *ptzOperationOptions) interface{} {
 		"op":      ptzOperation.Operation,
 	}
 
+	// the camera expects the preset or patrol index as id
 	if ptzOperation.Index != nil {
-		param["index"] = ptzOperation.Index
+		param["id"] = ptzOperation.Index
 	}
 
 	if ptzOperation.Speed != nil {
@@ -61,8 +121,10 @@ func ptzOperation(ptzOperation *ptzOperationOptions) interface{} {
 	}
 }
 
-func (pm *PtzMixin) GetPreset() func(handler *rest.RestHandler) (map[string]int, error) {
-	return func(handler *rest.RestHandler) (map[string]int, error) {
+// GetPreset Get every preset slot of the camera, including disabled slots and duplicate names.
+// Only presets with Enable set to 1 hold a position.
+func (pm *PtzMixin) GetPreset() func(handler *rest.RestHandler) ([]*models.PtzPreset, error) {
+	return func(handler *rest.RestHandler) ([]*models.PtzPreset, error) {
 		payload := map[string]interface{}{
 			"cmd":    "GetPtzPreset",
 			"action": 1,
@@ -73,26 +135,18 @@ func (pm *PtzMixin) GetPreset() func(handler *rest.RestHandler) (map[string]int,
 		result, err := handler.Request("POST", payload, "GetPtzPreset")
 
 		if err != nil {
-			return map[string]int{}, err
+			return nil, err
 		}
 
 		var presets []*models.PtzPreset
 
 		err = json.Unmarshal(result.Value["PtzPreset"], &presets)
 
-		presetMap := make(map[string]int, 2)
-
 		if err != nil {
-			return presetMap, err
+			return nil, err
 		}
 
-		for _, p := range presets {
-			if p.Enable == 1 {
-				presetMap[p.Name] = p.Index
-			}
-		}
-
-		return presetMap, nil
+		return presets, nil
 	}
 }
 
@@ -159,7 +213,7 @@ func (pm *PtzMixin) AddPreset(ptzOptions ...OptionPtzPreset) func(handler *rest.
 	return func(handler *rest.RestHandler) (bool, error) {
 		payload := ptzPreset(true, presetOptions.Index, presetOptions.Name)
 
-		result, err := handler.Request("POST", payload, "PtzCtrl")
+		result, err := handler.Request("POST", payload, "SetPtzPreset")
 
 		if err != nil {
 			return false, err
@@ -200,7 +254,7 @@ func (pm *PtzMixin) RemovePreset(ptzOptions ...OptionPtzPreset) func(handler *re
 	return func(handler *rest.RestHandler) (bool, error) {
 		payload := ptzPreset(false, presetOptions.Index, presetOptions.Name)
 
-		result, err := handler.Request("POST", payload, "PtzPreset")
+		result, err := handler.Request("POST", payload, "SetPtzPreset")
 
 		if err != nil {
 			return false, err
@@ -222,6 +276,142 @@ func (pm *PtzMixin) RemovePreset(ptzOptions ...OptionPtzPreset) func(handler *re
 	}
 }
 
+// SyncPresets Add and rename presets so the camera's enabled presets match the desired list, with
+// PtzPresetSyncOptionRemoveUnlisted the presets missing from the list are removed as well.
+// Presets are matched by their index (id), the desired Enable and Channel fields are ignored.
+// The camera can only store its current view, so added presets are stored wherever the camera is pointing and
+// renamed presets are moved to first, given time to settle and then stored again under the new name.
+// The returned diff lists the changes that were applied, or that would be applied on a dry run. When a change fails
+// the diff holds the changes applied so far.
+func (pm *PtzMixin) SyncPresets(ctx context.Context, desired []models.PtzPreset,
+	syncOptions ...OptionPtzPresetSync) func(handler *rest.RestHandler) (*models.PtzPresetDiff, error) {
+	options := &ptzPresetSyncOptions{
+		Settle: 3 * time.Second,
+	}
+
+	for _, op := range syncOptions {
+		op(options)
+	}
+
+	return func(handler *rest.RestHandler) (*models.PtzPresetDiff, error) {
+		wanted := make(map[int]models.PtzPreset, len(desired))
+
+		for _, p := range desired {
+			if _, ok := wanted[p.Index]; ok {
+				return nil, fmt.Errorf("desired presets contain preset %d more than once", p.Index)
+			}
+
+			if p.Name == "" {
+				return nil, fmt.Errorf("desired preset %d has no name", p.Index)
+			}
+
+			wanted[p.Index] = p
+		}
+
+		presets, err := pm.GetPreset()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		current := make(map[int]*models.PtzPreset, len(presets))
+
+		for _, p := range presets {
+			if p.Enable == 1 {
+				current[p.Index] = p
+			}
+		}
+
+		plan := &models.PtzPresetDiff{}
+
+		for _, p := range desired {
+			existing, ok := current[p.Index]
+
+			if !ok {
+				plan.Added = append(plan.Added, models.PtzPreset{Channel: 0, Enable: 1, Index: p.Index, Name: p.Name})
+			} else if existing.Name != p.Name {
+				plan.Renamed = append(plan.Renamed, models.PtzPresetRename{
+					Index: p.Index,
+					From:  existing.Name,
+					To:    p.Name,
+				})
+			}
+		}
+
+		if options.RemoveUnlisted {
+			for _, p := range presets {
+				if _, ok := wanted[p.Index]; p.Enable == 1 && !ok {
+					plan.Removed = append(plan.Removed, *p)
+				}
+			}
+		}
+
+		sort.Slice(plan.Added, func(i, j int) bool { return plan.Added[i].Index < plan.Added[j].Index })
+		sort.Slice(plan.Renamed, func(i, j int) bool { return plan.Renamed[i].Index < plan.Renamed[j].Index })
+		sort.Slice(plan.Removed, func(i, j int) bool { return plan.Removed[i].Index < plan.Removed[j].Index })
+
+		if options.DryRun {
+			return plan, nil
+		}
+
+		applied := &models.PtzPresetDiff{}
+
+		// removals first so the camera has room for the new presets
+		for _, p := range plan.Removed {
+			_, err := pm.RemovePreset(PtzOptionPresetIndex(p.Index), PtzOptionsPresetName(p.Name))(handler)
+
+			if err != nil {
+				return applied, err
+			}
+
+			applied.Removed = append(applied.Removed, p)
+		}
+
+		for _, r := range plan.Renamed {
+			index := r.Index
+
+			_, err := pm.GoToPreset(PtzOptionOpsIndex(&index))(handler)
+
+			if err != nil {
+				return applied, err
+			}
+
+			timer := time.NewTimer(options.Settle)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return applied, ctx.Err()
+			case <-timer.C:
+			}
+
+			_, err = pm.AddPreset(PtzOptionPresetIndex(r.Index), PtzOptionsPresetName(r.To))(handler)
+
+			if err != nil {
+				return applied, err
+			}
+
+			applied.Renamed = append(applied.Renamed, r)
+		}
+
+		for _, p := range plan.Added {
+			if err := ctx.Err(); err != nil {
+				return applied, err
+			}
+
+			_, err := pm.AddPreset(PtzOptionPresetIndex(p.Index), PtzOptionsPresetName(p.Name))(handler)
+
+			if err != nil {
+				return applied, err
+			}
+
+			applied.Added = append(applied.Added, p)
+		}
+
+		return applied, nil
+	}
+}
+
 // Move the camera to the right
 // The operation speed is optional and will fallback to defaults. Other operations will be ignored.
 // Defaults:
@@ -657,6 +847,580 @@ func (pm *PtzMixin) AutoMovement() func(handler *rest.RestHandler) (bool, error)
 	}
 }
 
+// GetPtzPatrol Get the camera's patrols (cruises)
+func (pm *PtzMixin) GetPtzPatrol() func(handler *rest.RestHandler) ([]*models.PtzPatrol, error) {
+	return func(handler *rest.RestHandler) ([]*models.PtzPatrol, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetPtzPatrol",
+			"action": 1,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetPtzPatrol")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var patrols []*models.PtzPatrol
+
+		err = json.Unmarshal(result.Value["PtzPatrol"], &patrols)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return patrols, nil
+	}
+}
+
+// SetPtzPatrol Create or replace the patrol with the same id
+// Every preset of the patrol must be an enabled preset returned by GetPreset, dwell times must be positive and
+// speeds within 1-64.
+func (pm *PtzMixin) SetPtzPatrol(patrol *models.PtzPatrol) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if patrol == nil || len(patrol.Preset) == 0 {
+			return false, fmt.Errorf("patrol needs at least one preset")
+		}
+
+		presets, err := pm.GetPreset()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		presetIds := make(map[int]bool, len(presets))
+		var known []int
+
+		for _, p := range presets {
+			if p.Enable == 1 {
+				presetIds[p.Index] = true
+				known = append(known, p.Index)
+			}
+		}
+
+		sort.Ints(known)
+
+		for i, p := range patrol.Preset {
+			if !presetIds[p.Index] {
+				return false, fmt.Errorf("patrol %d stop %d: preset %d is not an enabled preset on the camera, enabled presets are %v",
+					patrol.Index, i, p.Index, known)
+			}
+
+			if p.DwellTime <= 0 {
+				return false, fmt.Errorf("patrol %d stop %d: dwell time must be positive, got %d",
+					patrol.Index, i, p.DwellTime)
+			}
+
+			if p.Speed < 1 || p.Speed > 64 {
+				return false, fmt.Errorf("patrol %d stop %d: speed must be within 1-64, got %d",
+					patrol.Index, i, p.Speed)
+			}
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetPtzPatrol",
+			"action": 0,
+			"param": map[string]interface{}{
+				"PtzPatrol": patrol,
+			},
+		}
+
+		if err := ptzRequest(handler, payload, "SetPtzPatrol", "set ptz patrol"); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// StartPatrol Start the patrol with the given id
+func (pm *PtzMixin) StartPatrol(patrolId int) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		err := ptzCommand(handler, &ptzOperationOptions{
+			Operation: "StartPatrol",
+			Index:     &patrolId,
+		})
+
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// StopPatrol Stop the patrol with the given id
+func (pm *PtzMixin) StopPatrol(patrolId int) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		err := ptzCommand(handler, &ptzOperationOptions{
+			Operation: "StopPatrol",
+			Index:     &patrolId,
+		})
+
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// helper to send a ptz request and check the camera accepted it, action describes the request in the error
+func ptzRequest(handler *rest.RestHandler, payload interface{}, command string, action string) error {
+	result, err := handler.Request("POST", payload, command)
+
+	if err != nil {
+		return err
+	}
+
+	var respCode int
+
+	err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+	if err != nil {
+		return err
+	}
+
+	if respCode != 200 {
+		return fmt.Errorf("camera could not %s. camera responded with %v", action, result.Value)
+	}
+
+	return nil
+}
+
+// helper to send a single ptz operation and check the camera accepted it
+func ptzCommand(handler *rest.RestHandler, ptzOperations *ptzOperationOptions) error {
+	return ptzRequest(handler, ptzOperation(ptzOperations), "PtzCtrl", ptzOperations.Operation)
+}
+
+// helper to move in a direction until the duration elapses or ctx is done, the stop is always sent
+func ptzMoveFor(ctx context.Context, handler *rest.RestHandler, direction enum.PtzDirection, speed int,
+	duration time.Duration) error {
+
+	moveErr := ptzCommand(handler, &ptzOperationOptions{
+		Operation: direction.Value(),
+		Speed:     &speed,
+	})
+
+	if moveErr == nil {
+		timer := time.NewTimer(duration)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			moveErr = ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// the stop is sent even when starting failed, the camera may have started moving before the error
+	stopErr := ptzCommand(handler, &ptzOperationOptions{Operation: "Stop"})
+
+	if moveErr != nil {
+		return moveErr
+	}
+
+	return stopErr
+}
+
+// MoveFor Move the camera in the direction at the given speed for the duration, then stop.
+// Stop is always sent, also when ctx is cancelled or starting the move failed, in which case that error is returned.
+func (pm *PtzMixin) MoveFor(ctx context.Context, direction enum.PtzDirection, speed int,
+	duration time.Duration) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		err := ptzMoveFor(ctx, handler, direction, speed, duration)
+
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// MoveSteps Move the camera by a number of steps in the direction.
+// Each step moves at the given speed for stepDuration and is followed by a stop, which makes the distance travelled
+// repeatable regardless of the network latency.
+// Stop is always sent, also when ctx is cancelled part way through.
+func (pm *PtzMixin) MoveSteps(ctx context.Context, direction enum.PtzDirection, speed int, steps int,
+	stepDuration time.Duration) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		for i := 0; i < steps; i++ {
+			err := ptzMoveFor(ctx, handler, direction, speed, stepDuration)
+
+			if err != nil {
+				return false, fmt.Errorf("step %d of %d: %w", i+1, steps, err)
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// PtzWatchdog stops the camera's ptz movement when no keep-alive arrives within the configured window.
+type PtzWatchdog struct {
+	handler   *rest.RestHandler
+	window    time.Duration
+	keepAlive chan struct{}
+	errChan   chan error
+	done      chan struct{}
+}
+
+// StartPtzWatchdog Start a watchdog which sends Stop when KeepAlive has not been called for window.
+// Call KeepAlive whenever a movement is started or continued; after a stop the watchdog waits for the next KeepAlive
+// before it arms again. A final Stop is sent when ctx is done.
+func (pm *PtzMixin) StartPtzWatchdog(ctx context.Context, window time.Duration) func(
+	handler *rest.RestHandler) *PtzWatchdog {
+	return func(handler *rest.RestHandler) *PtzWatchdog {
+		w := &PtzWatchdog{
+			handler:   handler,
+			window:    window,
+			keepAlive: make(chan struct{}, 1),
+			errChan:   make(chan error, 1),
+			done:      make(chan struct{}),
+		}
+
+		go w.run(ctx)
+
+		return w
+	}
+}
+
+// KeepAlive tells the watchdog the movement is still wanted
+func (w *PtzWatchdog) KeepAlive() {
+	select {
+	case w.keepAlive <- struct{}{}:
+	default: // a keep-alive is already pending
+	}
+}
+
+// Errors returns the errors of the Stop commands sent by the watchdog.
+// Errors are dropped when nobody is reading them.
+func (w *PtzWatchdog) Errors() <-chan error {
+	return w.errChan
+}
+
+// Done is closed once the watchdog has sent its final Stop after ctx is done
+func (w *PtzWatchdog) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *PtzWatchdog) stop() {
+	err := ptzCommand(w.handler, &ptzOperationOptions{Operation: "Stop"})
+
+	if err != nil {
+		select {
+		case w.errChan <- err:
+		default:
+		}
+	}
+}
+
+func (w *PtzWatchdog) run(ctx context.Context) {
+	defer close(w.done)
+
+	timer := time.NewTimer(w.window)
+	// disarmed until the first keep-alive
+	if !timer.Stop() {
+		<-timer.C
+	}
+
+	armed := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.stop()
+			return
+		case <-w.keepAlive:
+			if armed && !timer.Stop() {
+				<-timer.C
+			}
+
+			timer.Reset(w.window)
+			armed = true
+		case <-timer.C:
+			armed = false
+			w.stop()
+		}
+	}
+}
+
+// GetPtzGuard Get the camera's guard (home) position settings
+func (pm *PtzMixin) GetPtzGuard() func(handler *rest.RestHandler) (*models.PtzGuard, error) {
+	return func(handler *rest.RestHandler) (*models.PtzGuard, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetPtzGuard",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetPtzGuard")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var guard *models.PtzGuard
+
+		err = json.Unmarshal(result.Value["PtzGuard"], &guard)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if guard == nil {
+			return nil, fmt.Errorf("camera did not return its ptz guard settings")
+		}
+
+		return guard, nil
+	}
+}
+
+// SetPtzGuard Set the camera's guard (home) position settings
+// The camera's current enable state and timeout are kept unless passed as options.
+// Pass PtzGuardOptionSaveCurrentPosition(true) to store the current view as the guard position.
+func (pm *PtzMixin) SetPtzGuard(guardOptions ...OptionPtzGuard) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		current, err := pm.GetPtzGuard()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		guard := &ptzGuardOptions{
+			Enable:       current.Enable,
+			Timeout:      current.Timeout,
+			SavePosition: false,
+		}
+
+		for _, op := range guardOptions {
+			op(guard)
+		}
+
+		if guard.Enable == 1 && current.ExistPos == 0 && !guard.SavePosition {
+			return false, fmt.Errorf("camera has no guard position yet, save the current position to enable it")
+		}
+
+		payload := ptzGuard("setPos", guard)
+
+		if err := ptzRequest(handler, payload, "SetPtzGuard", "set ptz guard"); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// SetPtzGuardFromPreset Move the camera to the preset, wait for it to settle and store that view as the guard
+// position. The remaining guard settings can be passed as options.
+func (pm *PtzMixin) SetPtzGuardFromPreset(ctx context.Context, presetIndex int, settle time.Duration,
+	guardOptions ...OptionPtzGuard) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		_, err := pm.GoToPreset(PtzOptionOpsIndex(&presetIndex))(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		timer := time.NewTimer(settle)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+
+		// copy the options, appending to the caller's slice could overwrite its spare capacity
+		options := append(append([]OptionPtzGuard{}, guardOptions...), PtzGuardOptionSaveCurrentPosition(true))
+
+		return pm.SetPtzGuard(options...)(handler)
+	}
+}
+
+// GoToGuard Move the camera to its guard position
+// PtzCtrl, which ptzOperation builds, has no guard operation: the camera only moves to its guard position through
+// SetPtzGuard with the "toPos" command string.
+func (pm *PtzMixin) GoToGuard() func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		payload := ptzGuard("toPos", nil)
+
+		if err := ptzRequest(handler, payload, "SetPtzGuard", "go to ptz guard"); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// PtzCheck Start the mechanical self-check, the camera pans and tilts to its limits to recalibrate its position.
+// Use GetPtzCheckState or WaitForPtzCheck to follow its progress.
+func (pm *PtzMixin) PtzCheck() func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		payload := map[string]interface{}{
+			"cmd":    "PtzCheck",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		if err := ptzRequest(handler, payload, "PtzCheck", "start ptz check"); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// GetPtzCheckState Get the progress of the self-check started by PtzCheck
+func (pm *PtzMixin) GetPtzCheckState() func(handler *rest.RestHandler) (enum.PtzCheckState, error) {
+	return func(handler *rest.RestHandler) (enum.PtzCheckState, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetPtzCheckState",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetPtzCheckState")
+
+		if err != nil {
+			return enum.PTZ_CHECK_IDLE, err
+		}
+
+		var state int
+
+		err = json.Unmarshal(result.Value["PtzCheckState"], &state)
+
+		if err != nil {
+			return enum.PTZ_CHECK_IDLE, err
+		}
+
+		return enum.PtzCheckStateFromValue(state)
+	}
+}
+
+// WaitForPtzCheck Poll the self-check state every interval until the check has finished or ctx is done
+func (pm *PtzMixin) WaitForPtzCheck(ctx context.Context, interval time.Duration) func(
+	handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			state, err := pm.GetPtzCheckState()(handler)
+
+			if err != nil {
+				return false, err
+			}
+
+			if state == enum.PTZ_CHECK_FINISHED {
+				return true, nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return false, fmt.Errorf("ptz check still %s: %w", state, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// helper to make sure the camera has an RS485 port before touching the ptz serial settings
+func checkPtzSerialSupport(handler *rest.RestHandler) error {
+	sm := &SystemMixin{}
+
+	deviceInfo, err := sm.GetDeviceInformation()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	if deviceInfo == nil || deviceInfo.B485 == 0 {
+		return fmt.Errorf("camera does not support ptz control over RS485")
+	}
+
+	return nil
+}
+
+// GetPtzSerial Get the RS485 settings used to control an external PTZ head
+// Only models whose DeviceInformation.B485 is set support this, others return an error.
+func (pm *PtzMixin) GetPtzSerial() func(handler *rest.RestHandler) (*models.PtzSerial, error) {
+	return func(handler *rest.RestHandler) (*models.PtzSerial, error) {
+		if err := checkPtzSerialSupport(handler); err != nil {
+			return nil, err
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "GetPtzSerial",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetPtzSerial")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var ptzSerial *models.PtzSerial
+
+		err = json.Unmarshal(result.Value["PtzSerial"], &ptzSerial)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ptzSerial == nil {
+			return nil, fmt.Errorf("camera did not return its ptz serial settings")
+		}
+
+		return ptzSerial, nil
+	}
+}
+
+// SetPtzSerial Change the RS485 settings, the settings not passed as options are kept
+// Only models whose DeviceInformation.B485 is set support this, others return an error.
+func (pm *PtzMixin) SetPtzSerial(serialOptions ...OptionPtzSerial) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		ptzSerial, err := pm.GetPtzSerial()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		for _, op := range serialOptions {
+			op(ptzSerial)
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetPtzSerial",
+			"action": 0,
+			"param": map[string]interface{}{
+				"PtzSerial": ptzSerial,
+			},
+		}
+
+		if err := ptzRequest(handler, payload, "SetPtzSerial", "set ptz serial"); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
 // Set the Ptz Operation Speed
 func PtzOptionOpsSpeed(speed int) OptionPtzOperation {
 	return func(p *ptzOperationOptions) {
@@ -684,3 +1448,100 @@ func PtzOptionsPresetName(name string) OptionPtzPreset {
 		p.Name = name
 	}
 }
+
+// Only report the changes SyncPresets would make without changing the camera
+func PtzPresetSyncOptionDryRun(dryRun bool) OptionPtzPresetSync {
+	return func(p *ptzPresetSyncOptions) {
+		p.DryRun = dryRun
+	}
+}
+
+// Remove the enabled presets that are not in the desired list
+// Default: false
+func PtzPresetSyncOptionRemoveUnlisted(remove bool) OptionPtzPresetSync {
+	return func(p *ptzPresetSyncOptions) {
+		p.RemoveUnlisted = remove
+	}
+}
+
+// Set how long SyncPresets waits for the camera to reach a preset before storing it under its new name
+// Default: 3 seconds
+func PtzPresetSyncOptionSettle(settle time.Duration) OptionPtzPresetSync {
+	return func(p *ptzPresetSyncOptions) {
+		p.Settle = settle
+	}
+}
+
+// Enable or disable returning to the guard position
+func PtzGuardOptionEnable(enable bool) OptionPtzGuard {
+	return func(p *ptzGuardOptions) {
+		if enable {
+			p.Enable = 1
+		} else {
+			p.Enable = 0
+		}
+	}
+}
+
+// Set the idle time in seconds after which the camera returns to the guard position
+func PtzGuardOptionTimeout(seconds int) OptionPtzGuard {
+	return func(p *ptzGuardOptions) {
+		p.Timeout = seconds
+	}
+}
+
+// Store the camera's current view as the guard position
+func PtzGuardOptionSaveCurrentPosition(save bool) OptionPtzGuard {
+	return func(p *ptzGuardOptions) {
+		p.SavePosition = save
+	}
+}
+
+// Set the RS485 baud rate, e.g. 2400 or 9600
+func PtzSerialOptionBaudRate(baudRate int) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.BaudRate = baudRate
+	}
+}
+
+// Set the address of the PTZ head on the RS485 bus
+func PtzSerialOptionAddress(address int) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.CtrlAddr = address
+	}
+}
+
+// Set the control protocol, e.g. PELCO_D or PELCO_P
+func PtzSerialOptionProtocol(protocol string) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.CtrlProtocol = protocol
+	}
+}
+
+// Set the data bits, e.g. CS8
+func PtzSerialOptionDataBit(dataBit string) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.DataBit = dataBit
+	}
+}
+
+// Set the flow control, e.g. none, hard or xon
+func PtzSerialOptionFlowControl(flowCtrl string) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.FlowCtrl = flowCtrl
+	}
+}
+
+// Set the parity, e.g. none, odd or even
+func PtzSerialOptionParity(parity string) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.Parity = parity
+	}
+}
+
+// Set the number of stop bits, 1 or 2
+func PtzSerialOptionStopBit(stopBit int) OptionPtzSerial {
+	return func(p *models.PtzSerial) {
+		p.StopBit = stopBit
+	}
+}
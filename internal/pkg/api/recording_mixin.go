@@ -10,27 +10,34 @@ import (
 
 type RecordingMixin struct{}
 
-type encoding struct {
-	audio         bool
-	mainBitRate   int
-	mainFrameRate int
-	mainProfile   string
-	mainSize      string
-	subBitRate    int
-	subFrameRate  int
-	subProfile    string
-	subSize       string
-}
-
-type OptionRecordingEncoding func(*encoding)
+type OptionRecordingEncoding func(*models.Encoding)
 
 // Get the camera's current encoding settings for "Clear" and "Fluent" profiles
 // See examples/response/GetEnc.json for example response data
 func (rm *RecordingMixin) GetRecordingEncoding() func(handler *rest.RestHandler) (*models.Encoding, error) {
 	return func(handler *rest.RestHandler) (*models.Encoding, error) {
+		encodingSettings, err := rm.GetRecordingEncodingSettings()(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return encodingSettings.Encoding, nil
+	}
+}
+
+// GetRecordingEncodingSettings Get the camera's current encoding settings together with the initial values and the
+// legal bit rates, frame rates and profiles for every main stream size the camera supports.
+// Initial and Range are nil when the camera does not report them or GetterOptionDetailed(false) is passed.
+// See examples/response/GetEnc.json for example response data
+func (rm *RecordingMixin) GetRecordingEncodingSettings(getterOptions ...OptionGetter) func(
+	handler *rest.RestHandler) (*models.EncodingSettings, error) {
+	getter := newGetterOptions(true, getterOptions)
+
+	return func(handler *rest.RestHandler) (*models.EncodingSettings, error) {
 		payload := map[string]interface{}{
 			"cmd":    "GetEnc",
-			"action": 1,
+			"action": getter.action,
 			"param": map[string]interface{}{
 				"channel": 0,
 			},
@@ -50,7 +57,24 @@ func (rm *RecordingMixin) GetRecordingEncoding() func(handler *rest.RestHandler)
 			return nil, err
 		}
 
-		return recordingData, nil
+		if recordingData == nil {
+			return nil, fmt.Errorf("camera did not return its encoding settings")
+		}
+
+		var recordingInitial *models.Encoding
+		var recordingRange models.EncodingRanges
+
+		err = unmarshalInitialAndRange(result, "Enc", &recordingInitial, &recordingRange)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.EncodingSettings{
+			Encoding: recordingData,
+			Initial:  recordingInitial,
+			Range:    recordingRange,
+		}, nil
 	}
 }
 
@@ -85,55 +109,78 @@ func (rm *RecordingMixin) GetRecordingAdvanced() func(handler *rest.RestHandler)
 	}
 }
 
-// Set the current camera encoding settings for "Clear" and "Fluent" profiles
-// Accepts optional parameters of OptionRecordingEncoding type
-// Defaults:
-// Audio: false
-// MainBitRate: 8192
-// MainFrameRate: 8
-// MainProfile: "High"
-// MainSize: "2560*1440"
-// SubBitRate: 160
-// SubFrameRate: 7
-// SubProfile: "High"
-// SubSize: "640*480"
-func (rm *RecordingMixin) SetRecordingEncoding(encodingOptions ...OptionRecordingEncoding) func(handler *rest.RestHandler) (bool, error) {
-	encoding := &encoding{
-		audio:         false,
-		mainBitRate:   8192,
-		mainFrameRate: 8,
-		mainProfile:   "High",
-		mainSize:      "2560*1440",
-		subBitRate:    160,
-		subFrameRate:  7,
-		subProfile:    "High",
-		subSize:       "640*480",
-	}
+// SetRecordingAdvanced Replace the camera's recording setup, e.g. with the setup read through GetRecordingAdvanced
+func (rm *RecordingMixin) SetRecordingAdvanced(recording *models.Recording) func(handler *rest.RestHandler) (bool,
+	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if recording == nil {
+			return false, fmt.Errorf("recording is required")
+		}
+
+		if err := recording.Schedule.Validate(); err != nil {
+			return false, err
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetRec",
+			"action": 0,
+			"param": map[string]interface{}{
+				"Rec": recording,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "SetRec")
 
-	for _, op := range encodingOptions {
-		op(encoding)
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not set recording. camera responded with %v", result.Value)
 	}
+}
+
+// Set the current camera encoding settings for "Clear" and "Fluent" profiles
+// Accepts optional parameters of OptionRecordingEncoding type.
+// The camera's current encoding is read first and only the fields passed as options are changed.
+// The result is validated against the camera's range for the chosen main stream size before it is sent,
+// returning a *models.ValidationError describing every offending field.
+func (rm *RecordingMixin) SetRecordingEncoding(encodingOptions ...OptionRecordingEncoding) func(handler *rest.RestHandler) (bool, error) {
 	return func(handler *rest.RestHandler) (bool, error) {
+		current, err := rm.GetRecordingEncodingSettings()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		encoding := current.Encoding
+
+		for _, op := range encodingOptions {
+			op(encoding)
+		}
+
+		if current.Range != nil {
+			if err := current.Range.Validate(encoding); err != nil {
+				return false, err
+			}
+		}
+
 		payload := map[string]interface{}{
 			"cmd":    "SetEnc",
 			"action": 0,
 			"param": map[string]interface{}{
-				"Enc": map[string]interface{}{
-					"audio":   encoding.audio,
-					"channel": 0,
-					"mainStream": map[string]interface{}{
-						"bitRate":   encoding.mainBitRate,
-						"frameRate": encoding.mainFrameRate,
-						"profile":   encoding.mainProfile,
-						"size":      encoding.mainSize,
-					},
-					"subStream": map[string]interface{}{
-						"bitRate":   encoding.subBitRate,
-						"frameRate": encoding.subFrameRate,
-						"profile":   encoding.subProfile,
-						"size":      encoding.subSize,
-					},
-				},
+				"Enc": encoding,
 			},
 		}
 
@@ -160,73 +207,78 @@ func (rm *RecordingMixin) SetRecordingEncoding(encodingOptions ...OptionRecordin
 }
 
 // Set audio on or off
-// Default: false
 func RecordingEncodingOptionAudio(audio bool) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.audio = audio
+	return func(encoding *models.Encoding) {
+		if audio {
+			encoding.Audio = enum.Enabled
+		} else {
+			encoding.Audio = enum.Disabled
+		}
 	}
 }
 
 // Set the main bit rate
-// Default: 8192
 func RecordingEncodingOptionMainBitRate(bitRate enum.MainBitRate) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.mainBitRate = bitRate.Value()
+	return func(encoding *models.Encoding) {
+		encoding.MainStream.BitRate = bitRate.Value()
 	}
 }
 
 // Set the main frame rate
-// Default: 8
 func RecordingEncodingOptionMainFrameRate(frameRate enum.MainFrameRate) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.mainFrameRate = frameRate.Value()
+	return func(encoding *models.Encoding) {
+		encoding.MainStream.FrameRate = frameRate.Value()
 	}
 }
 
 // Set the main profile
-// Default: High
 func RecordingEncodingOptionMainProfile(profile enum.RecordingProfile) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.mainProfile = profile.Value()
+	return func(encoding *models.Encoding) {
+		encoding.MainStream.Profile = profile.Value()
 	}
 }
 
 // Set the main size
-// Default: 2560*1440
 func RecordingEncodingOptionMainSize(size enum.MainSize) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.mainSize = size.Value()
+	return func(encoding *models.Encoding) {
+		encoding.MainStream.Size = size.Value()
 	}
 }
 
 // Set the sub bit rate
-// Default: 160
 func RecordingEncodingOptionSubBitRate(bitRate enum.SubBitRate) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.subBitRate = bitRate.Value()
+	return func(encoding *models.Encoding) {
+		encoding.SubStream.BitRate = bitRate.Value()
 	}
 }
 
 // Set the sub frame rate
-// Default: 7
 func RecordingEncodingOptionSubFrameRate(frameRate enum.SubFrameRate) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.subFrameRate = frameRate.Value()
+	return func(encoding *models.Encoding) {
+		encoding.SubStream.FrameRate = frameRate.Value()
 	}
 }
 
 // Set the sub profile
-// Default: High
 func RecordingEncodingOptionSubProfile(profile enum.RecordingProfile) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.subProfile = profile.Value()
+	return func(encoding *models.Encoding) {
+		encoding.SubStream.Profile = profile.Value()
 	}
 }
 
 // Set the sub size
-// Default: 640*480
 func RecordingEncodingOptionSubSize(size enum.SubSize) OptionRecordingEncoding {
-	return func(encoding *encoding) {
-		encoding.subSize = size.Value()
+	return func(encoding *models.Encoding) {
+		encoding.SubStream.Size = size.Value()
+	}
+}
+
+// Replace the whole encoding with the one given, typically a copy returned by GetRecordingEncoding.
+// Must be passed before any other option as it replaces every field.
+func RecordingEncodingOptionBase(base *models.Encoding) OptionRecordingEncoding {
+	return func(encoding *models.Encoding) {
+		if base != nil {
+			*encoding = *base
+		}
 	}
 }
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
+	"golang.org/x/net/context"
+	"io"
+	"time"
 )
 
 type SystemMixin struct{}
@@ -156,6 +160,244 @@ func (sm *SystemMixin) RebootCamera() func(handler *rest.RestHandler) (bool, err
 	}
 }
 
+type restoreOptions struct {
+	confirmed bool
+}
+
+type OptionRestore func(*restoreOptions)
+
+// Confirm the factory reset, Restore refuses to run without it
+func RestoreOptionConfirm() OptionRestore {
+	return func(r *restoreOptions) {
+		r.confirmed = true
+	}
+}
+
+// Restore Reset the camera to its factory defaults, every setting, user and password is lost and the camera reboots.
+// As this cannot be undone it must be confirmed with RestoreOptionConfirm, nothing is sent otherwise.
+func (sm *SystemMixin) Restore(confirmOptions ...OptionRestore) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		options := &restoreOptions{}
+
+		for _, op := range confirmOptions {
+			op(options)
+		}
+
+		if !options.confirmed {
+			return false, fmt.Errorf("factory reset was not confirmed, pass RestoreOptionConfirm to reset the camera")
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "Restore",
+			"action": 0,
+			"param":  map[string]interface{}{},
+		}
+
+		result, err := handler.Request("POST", payload, "Restore")
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not restore its factory defaults. camera responded with %v", result.Value)
+	}
+}
+
+// GetAutoMaint Get the camera's scheduled maintenance reboot
+func (sm *SystemMixin) GetAutoMaint() func(handler *rest.RestHandler) (*models.AutoMaint, error) {
+	return func(handler *rest.RestHandler) (*models.AutoMaint, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetAutoMaint",
+			"action": 0,
+			"param":  map[string]interface{}{},
+		}
+
+		result, err := handler.Request("POST", payload, "GetAutoMaint")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var autoMaint *models.AutoMaint
+
+		err = json.Unmarshal(result.Value["AutoMaint"], &autoMaint)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if autoMaint == nil {
+			return nil, fmt.Errorf("camera did not return its auto maintenance")
+		}
+
+		return autoMaint, nil
+	}
+}
+
+// SetAutoMaint Set the camera's scheduled maintenance reboot using the AutoMaintOption<prop> functions
+// The camera's current schedule is read first and only the fields passed as options are changed.
+// The time is the camera's local time.
+func (sm *SystemMixin) SetAutoMaint(autoMaintOptions ...options.AutoMaintOption) func(handler *rest.RestHandler) (
+	bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		autoMaint, err := sm.GetAutoMaint()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		for _, op := range autoMaintOptions {
+			op(autoMaint)
+		}
+
+		if err := autoMaint.Validate(); err != nil {
+			return false, err
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetAutoMaint",
+			"action": 0,
+			"param": map[string]interface{}{
+				"AutoMaint": autoMaint,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "SetAutoMaint")
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not set auto maintenance. camera responded with %v", result.Value)
+	}
+}
+
+// UpgradePrepare Announce a firmware upgrade, the firmware itself is sent with UploadFirmware
+// restoreConfig resets the camera's settings to their defaults as part of the upgrade.
+func (sm *SystemMixin) UpgradePrepare(fileName string, restoreConfig bool) func(handler *rest.RestHandler) (bool,
+	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		restore := 0
+
+		if restoreConfig {
+			restore = 1
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "UpgradePrepare",
+			"action": 0,
+			"param": map[string]interface{}{
+				"restoreCfg": restore,
+				"fileName":   fileName,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "UpgradePrepare")
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not prepare the upgrade. camera responded with %v", result.Value)
+	}
+}
+
+// UploadFirmware Upload a firmware image (.pak) after UpgradePrepare, the camera installs it and reboots.
+// Follow the installation with GetUpgradeStatus. The upload is aborted when ctx is done.
+func (sm *SystemMixin) UploadFirmware(ctx context.Context, fileName string, firmware io.Reader) func(
+	handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		result, err := handler.RequestMultipart(ctx, "Upgrade", "file", fileName, firmware)
+
+		if err != nil {
+			return false, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return false, err
+		}
+
+		if respCode == 200 {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("camera could not accept the firmware. camera responded with %v", result.Value)
+	}
+}
+
+// GetUpgradeStatus Get the progress of the firmware installation
+func (sm *SystemMixin) GetUpgradeStatus() func(handler *rest.RestHandler) (*models.UpgradeStatus, error) {
+	return func(handler *rest.RestHandler) (*models.UpgradeStatus, error) {
+		payload := map[string]interface{}{
+			"cmd":    "UpgradeStatus",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "UpgradeStatus")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var status *models.UpgradeStatus
+
+		err = json.Unmarshal(result.Value["Status"], &status)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if status == nil {
+			return nil, fmt.Errorf("camera did not return its upgrade status")
+		}
+
+		return status, nil
+	}
+}
+
 // Get the camera DST information
 // See examples/response/GetDSTInfo.json for example response data
 func (sm *SystemMixin) GetDstInformation() func(handler *rest.RestHandler) (*models.DstInformation,
@@ -191,6 +433,117 @@ func (sm *SystemMixin) GetDstInformation() func(handler *rest.RestHandler) (*mod
 	}
 }
 
+type syncTimeOptions struct {
+	location  *time.Location
+	threshold time.Duration
+}
+
+type OptionSyncTime func(*syncTimeOptions)
+
+// Set the zone the camera is synced to, its daylight saving rule is sent along
+// Default: the host's zone, time.Local
+func SyncTimeOptionLocation(location *time.Location) OptionSyncTime {
+	return func(s *syncTimeOptions) {
+		s.location = location
+	}
+}
+
+// Only set the camera's clock when it is off by more than threshold, the drift is returned either way
+// Default: 0, the clock is always set
+func SyncTimeOptionThreshold(threshold time.Duration) OptionSyncTime {
+	return func(s *syncTimeOptions) {
+		s.threshold = threshold
+	}
+}
+
+// SyncTimeFromHost Set the camera's clock, time zone and daylight saving rule to the host's
+// The drift is how far the camera's clock was off before, positive when it was ahead. The camera only keeps whole
+// seconds, so expect up to a second of drift on a synced camera. The date and hour formats are kept.
+// When the zone's daylight saving cannot be expressed, see models.DstFromLocation, the clock and time zone are still
+// set and a *models.DstError is returned.
+func (sm *SystemMixin) SyncTimeFromHost(timeOptions ...OptionSyncTime) func(handler *rest.RestHandler) (
+	time.Duration, error) {
+	return func(handler *rest.RestHandler) (time.Duration, error) {
+		options := &syncTimeOptions{
+			location: time.Local,
+		}
+
+		for _, op := range timeOptions {
+			op(options)
+		}
+
+		sent := time.Now()
+
+		dstData, timeData, err := sm.GetDstInformation()(handler)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if timeData == nil {
+			return 0, fmt.Errorf("camera did not return its time")
+		}
+
+		// the camera read its clock about halfway through the request
+		host := sent.Add(time.Since(sent) / 2)
+		drift := timeData.Time(dstData).Sub(host)
+
+		if options.threshold > 0 && drift <= options.threshold && drift >= -options.threshold {
+			return drift, nil
+		}
+
+		now := time.Now().In(options.location)
+
+		timeData.SetTime(now)
+
+		param := map[string]interface{}{
+			"Time": timeData,
+		}
+
+		dst, dstErr := models.DstFromLocation(options.location, now.Year())
+
+		if dstErr == nil {
+			// keep the camera's rule around when daylight saving is switched off
+			if dst.Enable == enum.Disabled && dstData != nil {
+				dst = dstData
+				dst.Enable = enum.Disabled
+			}
+
+			param["Dst"] = dst
+		}
+
+		payload := map[string]interface{}{
+			"cmd":    "SetTime",
+			"action": 0,
+			"param":  param,
+		}
+
+		result, err := handler.Request("POST", payload, "SetTime")
+
+		if err != nil {
+			return drift, err
+		}
+
+		var respCode int
+
+		err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+		if err != nil {
+			return drift, err
+		}
+
+		if respCode != 200 {
+			return drift, fmt.Errorf("camera could not sync its time. camera responded with %v", result.Value)
+		}
+
+		if dstErr != nil {
+			return drift, &models.DstError{Err: dstErr}
+		}
+
+		return drift, nil
+	}
+}
+
 // GetDeviceName Get the camera name
 func (sm *SystemMixin) GetDeviceName() func(handler *rest.RestHandler) (*models.DeviceName, error) {
 	return func(handler *rest.RestHandler) (*models.DeviceName, error) {
@@ -3,7 +3,10 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+	"golang.org/x/net/context"
+	"time"
 )
 
 type ZoomFocusMixin struct{}
@@ -291,6 +294,363 @@ func (zfm *ZoomFocusMixin) StopFocusing() func(handler *rest.RestHandler) (bool,
 	}
 }
 
+// GetZoomFocus Get the absolute zoom and focus position of the lens and, by default, their legal ranges
+func (zfm *ZoomFocusMixin) GetZoomFocus(getterOptions ...OptionGetter) func(handler *rest.RestHandler) (
+	*models.ZoomFocusSettings, error) {
+	getter := newGetterOptions(true, getterOptions)
+
+	return func(handler *rest.RestHandler) (*models.ZoomFocusSettings, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetZoomFocus",
+			"action": getter.action,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetZoomFocus")
+
+		if err != nil {
+			return nil, err
+		}
+
+		var zoomFocus *models.ZoomFocus
+
+		err = json.Unmarshal(result.Value["ZoomFocus"], &zoomFocus)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if zoomFocus == nil {
+			return nil, fmt.Errorf("camera did not return its zoom and focus position")
+		}
+
+		var zoomFocusInitial *models.ZoomFocus
+		var zoomFocusRange *models.ZoomFocusRange
+
+		err = unmarshalInitialAndRange(result, "ZoomFocus", &zoomFocusInitial, &zoomFocusRange)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.ZoomFocusSettings{
+			ZoomFocus: zoomFocus,
+			Initial:   zoomFocusInitial,
+			Range:     zoomFocusRange,
+		}, nil
+	}
+}
+
+// helper to move the lens to an absolute position, op is ZoomPos or FocusPos
+func startZoomFocus(handler *rest.RestHandler, op string, pos int) error {
+	payload := map[string]interface{}{
+		"cmd":    "StartZoomFocus",
+		"action": 0,
+		"param": map[string]interface{}{
+			"ZoomFocus": map[string]interface{}{
+				"channel": 0,
+				"op":      op,
+				"pos":     pos,
+			},
+		},
+	}
+
+	result, err := handler.Request("POST", payload, "StartZoomFocus")
+
+	if err != nil {
+		return err
+	}
+
+	var respCode int
+
+	err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+	if err != nil {
+		return err
+	}
+
+	if respCode != 200 {
+		return fmt.Errorf("camera could not move to %s %d. camera responded with %v", op, pos, result.Value)
+	}
+
+	return nil
+}
+
+// helper to move the lens to absolute zoom and focus positions after checking them against the camera's range.
+// A nil position is left where it is.
+func setZoomFocus(zfm *ZoomFocusMixin, handler *rest.RestHandler, zoomPos *int, focusPos *int) error {
+	settings, err := zfm.GetZoomFocus()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	target := *settings.ZoomFocus
+
+	if zoomPos != nil {
+		target.Zoom.Pos = *zoomPos
+	}
+
+	if focusPos != nil {
+		target.Focus.Pos = *focusPos
+	}
+
+	if settings.Range != nil {
+		if err := settings.Range.Validate(&target); err != nil {
+			return err
+		}
+	}
+
+	// zoom first, changing the zoom shifts the focus
+	if zoomPos != nil {
+		if err := startZoomFocus(handler, "ZoomPos", *zoomPos); err != nil {
+			return err
+		}
+	}
+
+	if focusPos != nil {
+		if err := startZoomFocus(handler, "FocusPos", *focusPos); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetZoomPosition Move the zoom to an absolute position within the range returned by GetZoomFocus
+func (zfm *ZoomFocusMixin) SetZoomPosition(pos int) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if err := setZoomFocus(zfm, handler, &pos, nil); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// SetFocusPosition Move the focus to an absolute position within the range returned by GetZoomFocus
+// The camera may refocus on its own while autofocus is enabled, see SetAutoFocus.
+func (zfm *ZoomFocusMixin) SetFocusPosition(pos int) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if err := setZoomFocus(zfm, handler, nil, &pos); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// SetZoomFocusPosition Move the zoom and then the focus to absolute positions
+func (zfm *ZoomFocusMixin) SetZoomFocusPosition(zoomPos int, focusPos int) func(handler *rest.RestHandler) (bool,
+	error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if err := setZoomFocus(zfm, handler, &zoomPos, &focusPos); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// GetAutoFocus Get whether autofocus is enabled
+func (zfm *ZoomFocusMixin) GetAutoFocus() func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		payload := map[string]interface{}{
+			"cmd":    "GetAutoFocus",
+			"action": 0,
+			"param": map[string]interface{}{
+				"channel": 0,
+			},
+		}
+
+		result, err := handler.Request("POST", payload, "GetAutoFocus")
+
+		if err != nil {
+			return false, err
+		}
+
+		var autoFocus *models.AutoFocus
+
+		err = json.Unmarshal(result.Value["AutoFocus"], &autoFocus)
+
+		if err != nil {
+			return false, err
+		}
+
+		if autoFocus == nil {
+			return false, fmt.Errorf("camera did not return its autofocus setting")
+		}
+
+		return autoFocus.Disable == 0, nil
+	}
+}
+
+// helper to send SetAutoFocus
+func setAutoFocus(handler *rest.RestHandler, enable bool) error {
+	disable := 1
+
+	if enable {
+		disable = 0
+	}
+
+	payload := map[string]interface{}{
+		"cmd":    "SetAutoFocus",
+		"action": 0,
+		"param": map[string]interface{}{
+			"AutoFocus": &models.AutoFocus{
+				Channel: 0,
+				Disable: disable,
+			},
+		},
+	}
+
+	result, err := handler.Request("POST", payload, "SetAutoFocus")
+
+	if err != nil {
+		return err
+	}
+
+	var respCode int
+
+	err = json.Unmarshal(result.Value["rspCode"], &respCode)
+
+	if err != nil {
+		return err
+	}
+
+	if respCode != 200 {
+		return fmt.Errorf("camera could not set autofocus. camera responded with %v", result.Value)
+	}
+
+	return nil
+}
+
+// SetAutoFocus Enable or disable autofocus, see TriggerAutoFocus to make the camera focus once
+func (zfm *ZoomFocusMixin) SetAutoFocus(enable bool) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if err := setAutoFocus(handler, enable); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// TriggerAutoFocus Make the camera focus once.
+// The camera has no one-shot focus command and switching autofocus off and back on alone does not make it refocus,
+// so autofocus is switched off, the focus nudged off its position and autofocus switched on again, which makes the
+// camera search for focus. Autofocus is left enabled, also when nudging the focus fails.
+func (zfm *ZoomFocusMixin) TriggerAutoFocus() func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		settings, err := zfm.GetZoomFocus()(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		if err := setAutoFocus(handler, false); err != nil {
+			return false, err
+		}
+
+		err = startZoomFocus(handler, "FocusPos", focusNudge(settings))
+
+		if enableErr := setAutoFocus(handler, true); enableErr != nil {
+			return false, enableErr
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// helper to pick a focus position a twentieth of the range away from the current one, staying within the range
+func focusNudge(settings *models.ZoomFocusSettings) int {
+	pos := settings.ZoomFocus.Focus.Pos
+
+	if settings.Range == nil || settings.Range.Focus.Pos.Max <= settings.Range.Focus.Pos.Min {
+		return pos + 1
+	}
+
+	allowed := settings.Range.Focus.Pos
+	step := (allowed.Max - allowed.Min) / 20
+
+	if step < 1 {
+		step = 1
+	}
+
+	if pos+step > allowed.Max {
+		return pos - step
+	}
+
+	return pos + step
+}
+
+// SavePresetWithZoomFocus Store the current view as a PTZ preset and return it with the current lens position,
+// keep the result to restore both with GoToPresetWithZoomFocus.
+func (zfm *ZoomFocusMixin) SavePresetWithZoomFocus(index int, name string) func(handler *rest.RestHandler) (
+	*models.ZoomFocusPreset, error) {
+	return func(handler *rest.RestHandler) (*models.ZoomFocusPreset, error) {
+		pm := &PtzMixin{}
+
+		_, err := pm.AddPreset(PtzOptionPresetIndex(index), PtzOptionsPresetName(name))(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		settings, err := zfm.GetZoomFocus(GetterOptionDetailed(false))(handler)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.ZoomFocusPreset{
+			Preset: models.PtzPreset{Channel: 0, Enable: 1, Index: index, Name: name},
+			Zoom:   settings.ZoomFocus.Zoom.Pos,
+			Focus:  settings.ZoomFocus.Focus.Pos,
+		}, nil
+	}
+}
+
+// GoToPresetWithZoomFocus Move to the preset, wait for the camera to settle and restore the saved zoom and focus
+func (zfm *ZoomFocusMixin) GoToPresetWithZoomFocus(ctx context.Context, preset *models.ZoomFocusPreset,
+	settle time.Duration) func(handler *rest.RestHandler) (bool, error) {
+	return func(handler *rest.RestHandler) (bool, error) {
+		if preset == nil {
+			return false, fmt.Errorf("preset with zoom and focus is required")
+		}
+
+		pm := &PtzMixin{}
+		index := preset.Preset.Index
+
+		_, err := pm.GoToPreset(PtzOptionOpsIndex(&index))(handler)
+
+		if err != nil {
+			return false, err
+		}
+
+		timer := time.NewTimer(settle)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := setZoomFocus(zfm, handler, &preset.Zoom, &preset.Focus); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
 // Set the zoom speed
 // default: 60
 func ZoomOptionSpeed(speed int) OptionZoomOperation {
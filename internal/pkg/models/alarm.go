@@ -0,0 +1,44 @@
+package models
+
+import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+
+// AlarmAction is what the camera does when motion is detected
+// RecChannel lists the channels that start recording.
+type AlarmAction struct {
+	Mail       enum.Toggle `json:"mail"`
+	Push       enum.Toggle `json:"push"`
+	RecChannel []int       `json:"recChannel"`
+}
+
+// AlarmSchedule arms the alarm per hour of the week, see Schedule for the table layout
+type AlarmSchedule struct {
+	Table string `json:"table"`
+}
+
+// AlarmScope is the detection area, Table holds one '0' or '1' per cell of the Cols x Rows grid, row by row
+type AlarmScope struct {
+	Cols  int    `json:"cols"`
+	Rows  int    `json:"rows"`
+	Table string `json:"table"`
+}
+
+// AlarmSensitivity is the detection sensitivity (1-50) during a part of the day
+type AlarmSensitivity struct {
+	Index       int `json:"id"`
+	BeginHour   int `json:"beginHour"`
+	BeginMin    int `json:"beginMin"`
+	EndHour     int `json:"endHour"`
+	EndMin      int `json:"endMin"`
+	Sensitivity int `json:"sensitivity"`
+}
+
+// MotionAlarm is the camera's motion detection alarm
+type MotionAlarm struct {
+	Action   AlarmAction        `json:"action"`
+	Channel  int                `json:"channel"`
+	Enable   enum.Toggle        `json:"enable"`
+	Schedule AlarmSchedule      `json:"schedule"`
+	Scope    AlarmScope         `json:"scope"`
+	Sens     []AlarmSensitivity `json:"sens"`
+	Type     string             `json:"type"`
+}
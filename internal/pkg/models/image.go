@@ -0,0 +1,151 @@
+package models
+
+import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+
+type Image struct {
+	Brightness int `json:"bright"`
+	Channel    int `json:"channel"`
+	Contrast   int `json:"contrast"`
+	Hue        int `json:"hue"`
+	Saturation int `json:"saturation"`
+	Sharpness  int `json:"sharpen"`
+}
+
+type MinMax struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// ImageRange holds the legal values the camera reported for each Image field
+type ImageRange struct {
+	Brightness MinMax `json:"bright"`
+	Contrast   MinMax `json:"contrast"`
+	Hue        MinMax `json:"hue"`
+	Saturation MinMax `json:"saturation"`
+	Sharpness  MinMax `json:"sharpen"`
+}
+
+type ImageSettings struct {
+	Image   *Image
+	Initial *Image
+	Range   *ImageRange
+}
+
+type Isp struct {
+	Channel      int    `json:"channel"`
+	AntiFlicker  string `json:"antiFlicker"`
+	Exposure     string `json:"exposure"`
+	Gain         MinMax `json:"gain"`
+	Shutter      MinMax `json:"shutter"`
+	BlueGain     int    `json:"blueGain"`
+	RedGain      int    `json:"redGain"`
+	WhiteBalance string `json:"whiteBalance"`
+	DayNight     string `json:"dayNight"`
+	BackLight    string `json:"backLight"`
+	Blc          int    `json:"blc"`
+	Drc          int    `json:"drc"`
+	Rotation     int    `json:"rotation"`
+	Mirroring    int    `json:"mirroring"`
+	Nr3d         int    `json:"nr3d"`
+}
+
+// AntiFlickerMode maps the camera's anti flicker value onto enum.AntiFlicker
+func (i *Isp) AntiFlickerMode() (enum.AntiFlicker, error) {
+	return enum.AntiFlickerFromValue(i.AntiFlicker)
+}
+
+// ExposureMode maps the camera's exposure value onto enum.Exposure
+func (i *Isp) ExposureMode() (enum.Exposure, error) {
+	return enum.ExposureFromValue(i.Exposure)
+}
+
+// WhiteBalanceMode maps the camera's white balance value onto enum.WhiteBalance
+func (i *Isp) WhiteBalanceMode() (enum.WhiteBalance, error) {
+	return enum.WhiteBalanceFromValue(i.WhiteBalance)
+}
+
+// DayNightMode maps the camera's day night value onto enum.DayNight
+func (i *Isp) DayNightMode() (enum.DayNight, error) {
+	return enum.DayNightFromValue(i.DayNight)
+}
+
+// BacklightMode maps the camera's backlight value onto enum.Backlight
+func (i *Isp) BacklightMode() (enum.Backlight, error) {
+	return enum.BacklightFromValue(i.BackLight)
+}
+
+// RotationMode maps the camera's rotation value onto enum.Rotation
+func (i *Isp) RotationMode() (enum.Rotation, error) {
+	return enum.RotationFromValue(i.Rotation)
+}
+
+// IspGainRange holds the legal values for both ends of a min/max Isp field such as gain or shutter
+type IspGainRange struct {
+	Min MinMax `json:"min"`
+	Max MinMax `json:"max"`
+}
+
+// IspRange holds the legal values the camera reported for each Isp field
+type IspRange struct {
+	AntiFlicker  []string     `json:"antiFlicker"`
+	Exposure     []string     `json:"exposure"`
+	Gain         IspGainRange `json:"gain"`
+	Shutter      IspGainRange `json:"shutter"`
+	BlueGain     MinMax       `json:"blueGain"`
+	RedGain      MinMax       `json:"redGain"`
+	WhiteBalance []string     `json:"whiteBalance"`
+	DayNight     []string     `json:"dayNight"`
+	BackLight    []string     `json:"backLight"`
+	Blc          MinMax       `json:"blc"`
+	Drc          MinMax       `json:"drc"`
+}
+
+type IspSettings struct {
+	Isp     *Isp
+	Initial *Isp
+	Range   *IspRange
+}
+
+// Validate checks every Image field against the camera's range
+func (r *ImageRange) Validate(image *Image) error {
+	v := &validator{}
+
+	v.minMax("brightness", image.Brightness, r.Brightness)
+	v.minMax("contrast", image.Contrast, r.Contrast)
+	v.minMax("hue", image.Hue, r.Hue)
+	v.minMax("saturation", image.Saturation, r.Saturation)
+	v.minMax("sharpness", image.Sharpness, r.Sharpness)
+
+	return v.result("image settings")
+}
+
+// Validate checks every Isp field against the camera's range
+func (r *IspRange) Validate(isp *Isp) error {
+	v := &validator{}
+
+	v.oneOfString("anti flicker", isp.AntiFlicker, r.AntiFlicker)
+	v.oneOfString("exposure", isp.Exposure, r.Exposure)
+	v.minMax("gain min", isp.Gain.Min, r.Gain.Min)
+	v.minMax("gain max", isp.Gain.Max, r.Gain.Max)
+
+	if isp.Gain.Min > isp.Gain.Max {
+		v.fail("gain min %d is greater than gain max %d", isp.Gain.Min, isp.Gain.Max)
+	}
+
+	v.minMax("shutter min", isp.Shutter.Min, r.Shutter.Min)
+	v.minMax("shutter max", isp.Shutter.Max, r.Shutter.Max)
+
+	if isp.Shutter.Min > isp.Shutter.Max {
+		v.fail("shutter min %d is greater than shutter max %d", isp.Shutter.Min, isp.Shutter.Max)
+	}
+
+	v.minMax("blue gain", isp.BlueGain, r.BlueGain)
+	v.minMax("red gain", isp.RedGain, r.RedGain)
+	v.oneOfString("white balance", isp.WhiteBalance, r.WhiteBalance)
+	v.oneOfString("day night", isp.DayNight, r.DayNight)
+	v.oneOfString("backlight", isp.BackLight, r.BackLight)
+	v.minMax("blc", isp.Blc, r.Blc)
+	v.minMax("drc", isp.Drc, r.Drc)
+
+	return v.result("advanced image settings")
+}
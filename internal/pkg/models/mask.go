@@ -1,5 +1,7 @@
 package models
 
+import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+
 type MaskAreaBlock struct {
 	Height int `json:"height"`
 	Width  int `json:"width"`
@@ -18,7 +20,7 @@ type MaskArea struct {
 }
 
 type MaskData struct {
-	Area    []MaskArea `json:"area"`
-	Channel int        `json:"channel"`
-	Enable  bool       `json:"enable"`
+	Area    []MaskArea  `json:"area"`
+	Channel int         `json:"channel"`
+	Enable  enum.Toggle `json:"enable"`
 }
\ No newline at end of file
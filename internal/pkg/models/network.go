@@ -1,13 +1,36 @@
 package models
 
-import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+import (
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"net"
+)
 
-// TODO: update with its actual data structure
-type ScanWifi struct {
+// WifiNetwork is a network found by ScanWifi
+// Signal is the strength reported by the camera, Encrypt the security in use, e.g. WPA2PSK or NONE.
+type WifiNetwork struct {
+	Ssid    string `json:"ssid"`
+	Signal  int    `json:"signal"`
+	Encrypt string `json:"encrypt"`
+	Channel int    `json:"channel"`
 }
 
-// TODO: update with its actual data structure
+// ScanWifi is the list of networks the camera can see
+type ScanWifi []WifiNetwork
+
 type Wifi struct {
+	Ssid     string `json:"ssid"`
+	Password string `json:"password"`
+}
+
+// WifiJoin is the outcome of joining a wireless network
+// Joined is false when the camera did not report the network as connected in time, the other fields then hold what
+// was last read. Ip is the address of the active link once joined, see NetworkGeneralStatic.
+type WifiJoin struct {
+	Joined     bool
+	Ssid       string
+	Signal     int
+	ActiveLink string
+	Ip         string
 }
 
 type NetworkGeneralDns struct {
@@ -16,6 +39,8 @@ type NetworkGeneralDns struct {
 	Dns2 string `json:"dns2"`
 }
 
+// NetworkGeneralStatic is the address of the camera's active link. While on DHCP the camera reports the address it
+// obtained here, see examples/response/GetNetworkGeneral.json.
 type NetworkGeneralStatic struct {
 	Gateway string `json:"gateway"`
 	Ip      string `json:"ip"`
@@ -31,47 +56,47 @@ type NetworkGeneral struct {
 }
 
 type NetworkDDNS struct {
-	Domain   string `json:"domain"`
-	Enable   bool   `json:"enable"`
-	Password string `json:"password"`
-	Type     string `json:"type"`
-	Username string `json:"userName"`
+	Domain   string      `json:"domain"`
+	Enable   enum.Toggle `json:"enable"`
+	Password string      `json:"password"`
+	Type     string      `json:"type"`
+	Username string      `json:"userName"`
 }
 
 type NetworkNTP struct {
-	Enable   bool   `json:"enable"`
-	Interval int    `json:"interval"`
-	Port     int    `json:"port"`
-	Server   string `json:"server"`
+	Enable   enum.Toggle `json:"enable"`
+	Interval int         `json:"interval"`
+	Port     int         `json:"port"`
+	Server   string      `json:"server"`
 }
 
 type NetworkEmail struct {
-	Username   string   `json:"username"`
-	Password   string   `json:"password"`
-	Addr1      string   `json:"addr1"`
-	Addr2      string   `json:"addr2"`
-	Addr3      string   `json:"addr3"`
-	Attachment string   `json:"attachment"`
-	Interval   string   `json:"interval"`
-	Nickname   string   `json:"nickName"`
-	Schedule   Schedule `json:"schedule"`
-	SmtpPort   int      `json:"smtpPort"`
-	SmtpServer string   `json:"smtpServer"`
-	SSL        bool     `json:"ssl"`
+	Username   string      `json:"userName"`
+	Password   string      `json:"password"`
+	Addr1      string      `json:"addr1"`
+	Addr2      string      `json:"addr2"`
+	Addr3      string      `json:"addr3"`
+	Attachment string      `json:"attachment"`
+	Interval   string      `json:"interval"`
+	Nickname   string      `json:"nickName"`
+	Schedule   Schedule    `json:"schedule"`
+	SmtpPort   int         `json:"smtpPort"`
+	SmtpServer string      `json:"smtpServer"`
+	SSL        enum.Toggle `json:"ssl"`
 }
 
 type NetworkFTP struct {
-	Username   string   `json:"userName"`
-	Password   string   `json:"password"`
-	Anonymous  bool     `json:"anonymous"`
-	Interval   int      `json:"interval"`
-	MaxSize    int      `json:"maxSize"`
-	Mode       int      `json:"mode"`
-	Port       int      `json:"port"`
-	RemoteDir  string   `json:"remoteDir"`
-	Schedule   Schedule `json:"schedule"`
-	Server     string   `json:"server"`
-	StreamType int      `json:"streamType"`
+	Username   string      `json:"userName"`
+	Password   string      `json:"password"`
+	Anonymous  enum.Toggle `json:"anonymous"`
+	Interval   int         `json:"interval"`
+	MaxSize    int         `json:"maxSize"`
+	Mode       int         `json:"mode"`
+	Port       int         `json:"port"`
+	RemoteDir  string      `json:"remoteDir"`
+	Schedule   Schedule    `json:"schedule"`
+	Server     string      `json:"server"`
+	StreamType int         `json:"streamType"`
 }
 
 type NetworkPush struct {
@@ -91,3 +116,85 @@ type NetworkPort struct {
 	RtspEnable  enum.Toggle `json:"rtspEnable"`
 	RtspPort    int         `json:"rtspPort"`
 }
+
+// DeliveryTest is the outcome of TestEmail or TestFtp.
+// When the camera could not deliver, Detail and RspCode hold the reason it gave.
+type DeliveryTest struct {
+	Success bool
+	Detail  string
+	RspCode int
+}
+
+// Validate checks the addresses of a static link and of fixed DNS servers
+func (n *NetworkGeneral) Validate() error {
+	v := &validator{}
+
+	if n.Type == enum.LINK_TYPE_STATIC.Value() {
+		ip := net.ParseIP(n.Static.Ip).To4()
+		mask := net.ParseIP(n.Static.Mask).To4()
+		gateway := net.ParseIP(n.Static.Gateway).To4()
+
+		if ip == nil {
+			v.fail("ip %q is not an IPv4 address", n.Static.Ip)
+		}
+
+		if mask == nil {
+			v.fail("mask %q is not an IPv4 mask", n.Static.Mask)
+		} else if ones, bits := net.IPMask(mask).Size(); ones == 0 && bits == 0 {
+			v.fail("mask %q is not a valid network mask", n.Static.Mask)
+		}
+
+		if gateway == nil {
+			v.fail("gateway %q is not an IPv4 address", n.Static.Gateway)
+		}
+
+		if ip != nil && mask != nil && gateway != nil {
+			network := &net.IPNet{IP: ip.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}
+
+			if !network.Contains(gateway) {
+				v.fail("gateway %s is outside the network %s", n.Static.Gateway, network)
+			}
+		}
+	} else if n.Type != enum.LINK_TYPE_DHCP.Value() {
+		v.fail("type %q is neither %s nor %s", n.Type, enum.LINK_TYPE_DHCP.Value(), enum.LINK_TYPE_STATIC.Value())
+	}
+
+	if n.Dns.Auto == 0 {
+		if net.ParseIP(n.Dns.Dns1) == nil {
+			v.fail("dns1 %q is not an IP address", n.Dns.Dns1)
+		}
+
+		if n.Dns.Dns2 != "" && net.ParseIP(n.Dns.Dns2) == nil {
+			v.fail("dns2 %q is not an IP address", n.Dns.Dns2)
+		}
+	}
+
+	return v.result("local link")
+}
+
+type NetworkUpnp struct {
+	Enable enum.Toggle `json:"enable"`
+}
+
+// NetworkP2p is the Reolink cloud (P2P) access, Uid is the camera's id used by the apps to reach it
+type NetworkP2p struct {
+	Enable enum.Toggle `json:"enable"`
+	Uid    string      `json:"uid"`
+}
+
+// NetworkStatus is the camera's runtime network state
+// Ip, Mask and Gateway are the addresses of the active link, also when they were obtained through DHCP, see
+// NetworkGeneralStatic. WifiSignal is only set while the active link is WiFi. NetThroughput is the current network
+// throughput reported by GetPerformance. The camera does not report the negotiated Ethernet speed.
+type NetworkStatus struct {
+	ActiveLink    string
+	Type          string
+	Ip            string
+	Mask          string
+	Gateway       string
+	Dns1          string
+	Dns2          string
+	Mac           string
+	WifiSignal    int
+	NetThroughput int
+}
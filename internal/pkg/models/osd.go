@@ -9,7 +9,17 @@ type OsdChannel struct {
 }
 
 type OsdTime struct {
+	Enable   enum.Toggle `json:"enable"`
+	Pos      string      `json:"pos"`
+	DateFmt  string      `json:"dateFmt,omitempty"`
+	HourFmt  *int        `json:"hourFmt,omitempty"`
+	Language string      `json:"language,omitempty"`
+}
+
+// OsdCustom is a free text overlay. Only some camera models support these.
+type OsdCustom struct {
 	Enable enum.Toggle `json:"enable"`
+	Text   string      `json:"text"`
 	Pos    string      `json:"pos"`
 }
 
@@ -19,4 +29,6 @@ type Osd struct {
 	OsdChannel OsdChannel  `json:"osdChannel"`
 	OsdTime    OsdTime     `json:"osdTime"`
 	Watermark  enum.Toggle `json:"watermark"`
+	FontSize   string      `json:"fontSize,omitempty"`
+	OsdCustom  []OsdCustom `json:"osdCustom,omitempty"`
 }
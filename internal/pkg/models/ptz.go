@@ -1,11 +1,95 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TODO: update
-type PtzOperation struct {}
+type PtzOperation struct{}
 
-type PtzPreset struct{
+type PtzPreset struct {
 	Channel int    `json:"channel"`
 	Enable  int    `json:"enable"`
 	Index   int    `json:"id"`
 	Name    string `json:"name"`
 }
+
+// PtzPatrolPreset is a single stop of a patrol
+// DwellTime is the number of seconds the camera stays at the preset, Speed is the speed used to move to it
+type PtzPatrolPreset struct {
+	DwellTime int `json:"dwellTime"`
+	Index     int `json:"id"`
+	Speed     int `json:"speed"`
+}
+
+// PtzPatrol is a cruise which visits the presets in order
+type PtzPatrol struct {
+	Channel int               `json:"channel"`
+	Enable  int               `json:"enable"`
+	Index   int               `json:"id"`
+	Name    string            `json:"name,omitempty"`
+	Running int               `json:"running"`
+	Preset  []PtzPatrolPreset `json:"preset"`
+}
+
+// PtzGuard is the guard (home) position the camera returns to after Timeout seconds of idle time
+type PtzGuard struct {
+	Channel  int `json:"channel"`
+	Enable   int `json:"benable"`
+	ExistPos int `json:"bexistPos"`
+	Timeout  int `json:"timeout"`
+}
+
+// PtzPresetRename is a preset that keeps its position but changes its name
+type PtzPresetRename struct {
+	Index int
+	From  string
+	To    string
+}
+
+// PtzPresetDiff lists the changes needed to bring the camera's presets in line with a desired preset list
+type PtzPresetDiff struct {
+	Added   []PtzPreset
+	Renamed []PtzPresetRename
+	Removed []PtzPreset
+}
+
+// Empty reports whether the camera's presets already match
+func (d *PtzPresetDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Renamed) == 0 && len(d.Removed) == 0
+}
+
+func (d *PtzPresetDiff) String() string {
+	if d.Empty() {
+		return "presets in sync"
+	}
+
+	var lines []string
+
+	for _, p := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %d %q", p.Index, p.Name))
+	}
+
+	for _, r := range d.Renamed {
+		lines = append(lines, fmt.Sprintf("~ %d %q -> %q", r.Index, r.From, r.To))
+	}
+
+	for _, p := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %d %q", p.Index, p.Name))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// PtzSerial holds the RS485 settings used to control an external PTZ head
+type PtzSerial struct {
+	BaudRate     int    `json:"baudRate"`
+	Channel      int    `json:"channel"`
+	CtrlAddr     int    `json:"ctrlAddr"`
+	CtrlProtocol string `json:"ctrlProtocol"`
+	DataBit      string `json:"dataBit"`
+	FlowCtrl     string `json:"flowCtrl"`
+	Parity       string `json:"parity"`
+	StopBit      int    `json:"stopBit"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+
 type RecordingMainStream struct {
 	BitRate   int    `json:"bitRate"`
 	FrameRate int    `json:"frameRate"`
@@ -15,16 +17,81 @@ type RecordingSubStream struct {
 }
 
 type Encoding struct {
-	Audio      bool                `json:"audio"`
+	Audio      enum.Toggle         `json:"audio"`
 	Channel    int                 `json:"channel"`
 	MainStream RecordingMainStream `json:"mainStream"`
 	SubStream  RecordingSubStream  `json:"subStream"`
 }
 
 type Recording struct {
-	Channel    int      `json:"channel"`
-	Overwrite  bool     `json:"overwrite"`
-	PostRecord string   `json:"postRec"`
-	PreRecord  bool     `json:"preRec"`
-	Schedule   Schedule `json:"schedule"`
+	Channel    int         `json:"channel"`
+	Overwrite  enum.Toggle `json:"overwrite"`
+	PostRecord string      `json:"postRec"`
+	PreRecord  enum.Toggle `json:"preRec"`
+	Schedule   Schedule    `json:"schedule"`
+}
+
+type EncodingStreamDefault struct {
+	BitRate   int `json:"bitRate"`
+	FrameRate int `json:"frameRate"`
+}
+
+// EncodingStreamRange holds the legal values for one stream at the given size
+type EncodingStreamRange struct {
+	BitRate   []int                 `json:"bitRate"`
+	Default   EncodingStreamDefault `json:"default"`
+	FrameRate []int                 `json:"frameRate"`
+	Profile   []string              `json:"profile"`
+	Size      string                `json:"size"`
+}
+
+// EncodingRange is one of the main stream sizes the camera supports along with its matching sub stream
+type EncodingRange struct {
+	MainStream EncodingStreamRange `json:"mainStream"`
+	SubStream  EncodingStreamRange `json:"subStream"`
+}
+
+// EncodingRanges holds one EncodingRange per main stream size
+type EncodingRanges []EncodingRange
+
+type EncodingSettings struct {
+	Encoding *Encoding
+	Initial  *Encoding
+	Range    EncodingRanges
+}
+
+// Validate checks the encoding against the camera's ranges.
+// The main stream size selects which range the remaining fields are checked against.
+func (ranges EncodingRanges) Validate(encoding *Encoding) error {
+	v := &validator{}
+
+	var sizes []string
+
+	for _, r := range ranges {
+		sizes = append(sizes, r.MainStream.Size)
+
+		if r.MainStream.Size != encoding.MainStream.Size {
+			continue
+		}
+
+		v.oneOfInt("main stream bit rate", encoding.MainStream.BitRate, r.MainStream.BitRate)
+		v.oneOfInt("main stream frame rate", encoding.MainStream.FrameRate, r.MainStream.FrameRate)
+		v.oneOfString("main stream profile", encoding.MainStream.Profile, r.MainStream.Profile)
+		v.oneOfInt("sub stream bit rate", encoding.SubStream.BitRate, r.SubStream.BitRate)
+		v.oneOfInt("sub stream frame rate", encoding.SubStream.FrameRate, r.SubStream.FrameRate)
+		v.oneOfString("sub stream profile", encoding.SubStream.Profile, r.SubStream.Profile)
+
+		if r.SubStream.Size != "" && r.SubStream.Size != encoding.SubStream.Size {
+			v.fail("sub stream size %q is not supported with main stream size %q, expected %q",
+				encoding.SubStream.Size, encoding.MainStream.Size, r.SubStream.Size)
+		}
+
+		return v.result("encoding")
+	}
+
+	if len(sizes) > 0 {
+		v.oneOfString("main stream size", encoding.MainStream.Size, sizes)
+	}
+
+	return v.result("encoding")
 }
@@ -1,6 +1,23 @@
 package models
 
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"strings"
+)
+
+// Schedule enables a feature per hour of the week.
+// Table holds one '0' or '1' per hour, 168 in total, starting at Sunday 00:00.
 type Schedule struct {
-	Enable bool   `json:"enable"`
-	Table  string `json:"table"`
-}
\ No newline at end of file
+	Enable enum.Toggle `json:"enable"`
+	Table  string      `json:"table"`
+}
+
+// Validate checks the table has an hour for every hour of the week
+func (s *Schedule) Validate() error {
+	if len(s.Table) != 168 || strings.Trim(s.Table, "01") != "" {
+		return fmt.Errorf("invalid schedule: table needs 168 hours of '0' or '1', got %q", s.Table)
+	}
+
+	return nil
+}
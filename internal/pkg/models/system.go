@@ -1,22 +1,29 @@
 package models
 
+import "github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+
+// DstInformation is the camera's daylight saving rule, see DstFromLocation.
+// Each transition is the weekday (0 is Sunday) of a week of the month, week 5 being the last one, at a wall clock
+// time. Offset is in hours.
 type DstInformation struct {
-	Enable       bool `json:"enable"`
-	EndHour      int  `json:"endHour"`
-	EndMin       int  `json:"endMin"`
-	EndMon       int  `json:"endMon"`
-	EndSec       int  `json:"endSec"`
-	EndWeek      int  `json:"endWeek"`
-	EndWeekday   int  `json:"endWeekday"`
-	Offset       int  `json:"offset"`
-	StartHour    int  `json:"startHour"`
-	StartMin     int  `json:"startMin"`
-	StartMon     int  `json:"startMon"`
-	StartSec     int  `json:"startSec"`
-	StartWeek    int  `json:"startWeek"`
-	StartWeekday int  `json:"startWeekday"`
+	Enable       enum.Toggle `json:"enable"`
+	EndHour      int         `json:"endHour"`
+	EndMin       int         `json:"endMin"`
+	EndMon       int         `json:"endMon"`
+	EndSec       int         `json:"endSec"`
+	EndWeek      int         `json:"endWeek"`
+	EndWeekday   int         `json:"endWeekday"`
+	Offset       int         `json:"offset"`
+	StartHour    int         `json:"startHour"`
+	StartMin     int         `json:"startMin"`
+	StartMon     int         `json:"startMon"`
+	StartSec     int         `json:"startSec"`
+	StartWeek    int         `json:"startWeek"`
+	StartWeekday int         `json:"startWeekday"`
 }
 
+// TimeInformation is the camera's wall clock. TimeZone is the standard offset in seconds west of UTC, the inverse of
+// Go's, e.g. 21600 for UTC-6.
 type TimeInformation struct {
 	Day      int    `json:"day"`
 	Hour     int    `json:"hour"`
@@ -72,3 +79,38 @@ type DeviceTime struct {
 	Dst  *DstInformation  `json:"Dst,omitempty"`
 	Time *TimeInformation `json:"Time,omitempty"`
 }
+
+// UpgradeStatus is the progress of a firmware upgrade, Code is non zero when the upgrade failed
+type UpgradeStatus struct {
+	Percent int `json:"Persent"`
+	Code    int `json:"code"`
+}
+
+// AutoMaint is the scheduled maintenance reboot, WeekDay is one of enum.MaintenanceDay's values
+type AutoMaint struct {
+	Enable  enum.Toggle `json:"enable"`
+	WeekDay string      `json:"weekDay"`
+	Hour    int         `json:"hour"`
+	Min     int         `json:"min"`
+	Sec     int         `json:"sec"`
+}
+
+// Day maps the camera's week day onto enum.MaintenanceDay
+func (a *AutoMaint) Day() (enum.MaintenanceDay, error) {
+	return enum.MaintenanceDayFromValue(a.WeekDay)
+}
+
+// Validate checks the day and the time of day
+func (a *AutoMaint) Validate() error {
+	v := &validator{}
+
+	if _, err := a.Day(); err != nil {
+		v.fail("%v", err)
+	}
+
+	v.minMax("hour", a.Hour, MinMax{Min: 0, Max: 23})
+	v.minMax("minute", a.Min, MinMax{Min: 0, Max: 59})
+	v.minMax("second", a.Sec, MinMax{Min: 0, Max: 59})
+
+	return v.result("auto maintenance")
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"time"
+)
+
+// DstError is returned by SyncTimeFromHost when the clock and time zone were set but the daylight saving rule of the
+// location could not be expressed, the camera keeps the rule it had
+type DstError struct {
+	Err error
+}
+
+func (e *DstError) Error() string {
+	return fmt.Sprintf("time set without daylight saving: %v", e.Err)
+}
+
+func (e *DstError) Unwrap() error {
+	return e.Err
+}
+
+// Location is the camera's standard time zone, without daylight saving
+func (t *TimeInformation) Location() *time.Location {
+	return fixedZone(-t.TimeZone)
+}
+
+// Time is the camera's clock, dst may be nil. The camera keeps its wall clock, daylight saving included, so the zone
+// of the result is the standard one plus the DST offset while dst is active.
+func (t *TimeInformation) Time(dst *DstInformation) time.Time {
+	wall := time.Date(t.Year, time.Month(t.Mon), t.Day, t.Hour, t.Min, t.Sec, 0, time.UTC)
+	offset := -t.TimeZone
+
+	if dst != nil && dst.Active(wall) {
+		offset += dst.Offset * 3600
+	}
+
+	return time.Date(t.Year, time.Month(t.Mon), t.Day, t.Hour, t.Min, t.Sec, 0, fixedZone(offset))
+}
+
+// SetTime sets the wall clock to tm and the time zone to the standard offset of tm's location, the date and hour
+// formats are kept. The daylight saving rule is set separately, see DstFromLocation.
+func (t *TimeInformation) SetTime(tm time.Time) {
+	t.Year = tm.Year()
+	t.Mon = int(tm.Month())
+	t.Day = tm.Day()
+	t.Hour = tm.Hour()
+	t.Min = tm.Minute()
+	t.Sec = tm.Second()
+	t.TimeZone = -standardOffset(tm.Location(), tm.Year())
+}
+
+// Active tells whether daylight saving is in effect at the wall clock time, the zone of wall is ignored
+func (d *DstInformation) Active(wall time.Time) bool {
+	if d.Enable != enum.Enabled {
+		return false
+	}
+
+	wall = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, time.UTC)
+
+	start := dstTransition(wall.Year(), d.StartMon, d.StartWeek, d.StartWeekday, d.StartHour, d.StartMin,
+		d.StartSec)
+	end := dstTransition(wall.Year(), d.EndMon, d.EndWeek, d.EndWeekday, d.EndHour, d.EndMin, d.EndSec)
+
+	// in the southern hemisphere daylight saving runs over the new year
+	if start.Before(end) {
+		return !wall.Before(start) && wall.Before(end)
+	}
+
+	return !wall.Before(start) || wall.Before(end)
+}
+
+// DstFromLocation builds the daylight saving rule of an IANA zone, e.g. time.LoadLocation("Europe/Berlin"), from its
+// transitions in year. A zone without daylight saving gives a disabled rule. Zones whose daylight saving is not a
+// whole number of hours, or that do not switch exactly twice a year, cannot be expressed and are refused.
+func DstFromLocation(loc *time.Location, year int) (*DstInformation, error) {
+	var transitions []time.Time
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	to := time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+
+		if offsetAt(day, loc) != offsetAt(next, loc) {
+			transitions = append(transitions, findTransition(day, next, loc))
+		}
+	}
+
+	if len(transitions) == 0 {
+		return &DstInformation{Enable: enum.Disabled}, nil
+	}
+
+	if len(transitions) != 2 {
+		return nil, fmt.Errorf("%s changes its offset %d times in %d, it has no yearly daylight saving rule",
+			loc, len(transitions), year)
+	}
+
+	start, end := transitions[0], transitions[1]
+
+	if offsetAt(start, loc) < offsetAt(start.Add(-time.Second), loc) {
+		start, end = end, start
+	}
+
+	standard := offsetAt(start.Add(-time.Second), loc)
+	daylight := offsetAt(start, loc)
+
+	if daylight-standard != offsetAt(end.Add(-time.Second), loc)-offsetAt(end, loc) || (daylight-standard)%3600 != 0 {
+		return nil, fmt.Errorf("daylight saving of %s in %d is not a whole number of hours", loc, year)
+	}
+
+	// each transition is given in the wall clock time it happens at
+	startWall := start.In(fixedZone(standard))
+	endWall := end.In(fixedZone(daylight))
+
+	return &DstInformation{
+		Enable:       enum.Enabled,
+		Offset:       (daylight - standard) / 3600,
+		StartMon:     int(startWall.Month()),
+		StartWeek:    weekOfMonth(startWall),
+		StartWeekday: int(startWall.Weekday()),
+		StartHour:    startWall.Hour(),
+		StartMin:     startWall.Minute(),
+		StartSec:     startWall.Second(),
+		EndMon:       int(endWall.Month()),
+		EndWeek:      weekOfMonth(endWall),
+		EndWeekday:   int(endWall.Weekday()),
+		EndHour:      endWall.Hour(),
+		EndMin:       endWall.Minute(),
+		EndSec:       endWall.Second(),
+	}, nil
+}
+
+func offsetAt(t time.Time, loc *time.Location) int {
+	_, offset := t.In(loc).Zone()
+	return offset
+}
+
+// the first second of the new offset between before and after
+func findTransition(before time.Time, after time.Time, loc *time.Location) time.Time {
+	offset := offsetAt(before, loc)
+
+	for after.Sub(before) > time.Second {
+		middle := before.Add(after.Sub(before) / 2).Truncate(time.Second)
+
+		if offsetAt(middle, loc) == offset {
+			before = middle
+		} else {
+			after = middle
+		}
+	}
+
+	return after
+}
+
+// the smaller offset of the year is taken as standard time
+func standardOffset(loc *time.Location, year int) int {
+	winter := offsetAt(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), loc)
+	summer := offsetAt(time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC), loc)
+
+	if summer < winter {
+		return summer
+	}
+
+	return winter
+}
+
+// the week of the month of the day's weekday, 5 when it is the last one
+func weekOfMonth(day time.Time) int {
+	if day.Day()+7 > daysIn(day.Year(), day.Month()) {
+		return 5
+	}
+
+	return (day.Day()-1)/7 + 1
+}
+
+// the wall clock time of the week's weekday in the month
+func dstTransition(year int, month int, week int, weekday int, hour int, minute int, second int) time.Time {
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	day := 1 + (weekday-int(first.Weekday())+7)%7 + (week-1)*7
+
+	for day > daysIn(year, time.Month(month)) {
+		day -= 7
+	}
+
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// a fixed zone named after its offset, e.g. UTC-06:00
+func fixedZone(offset int) *time.Location {
+	sign := "+"
+	abs := offset
+
+	if offset < 0 {
+		sign = "-"
+		abs = -offset
+	}
+
+	return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", sign, abs/3600, abs%3600/60), offset)
+}
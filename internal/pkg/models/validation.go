@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists every field of a setting that falls outside the range reported by the camera
+type ValidationError struct {
+	Setting string
+	Fields  []string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", v.Setting, strings.Join(v.Fields, "; "))
+}
+
+// collects the failed fields of one setting, result returns nil when nothing failed
+type validator struct {
+	err ValidationError
+}
+
+func (v *validator) minMax(field string, value int, allowed MinMax) {
+	// a range the camera did not report decodes as 0-0
+	if allowed.Min == 0 && allowed.Max == 0 {
+		return
+	}
+
+	if value < allowed.Min || value > allowed.Max {
+		v.err.Fields = append(v.err.Fields,
+			fmt.Sprintf("%s %d is outside the camera's range %d-%d", field, value, allowed.Min, allowed.Max))
+	}
+}
+
+func (v *validator) oneOfString(field string, value string, allowed []string) {
+	// an empty range means the camera did not report one
+	if len(allowed) == 0 {
+		return
+	}
+
+	for _, a := range allowed {
+		if a == value {
+			return
+		}
+	}
+
+	v.err.Fields = append(v.err.Fields,
+		fmt.Sprintf("%s %q is not one of the camera's values %q", field, value, allowed))
+}
+
+func (v *validator) oneOfInt(field string, value int, allowed []int) {
+	if len(allowed) == 0 {
+		return
+	}
+
+	for _, a := range allowed {
+		if a == value {
+			return
+		}
+	}
+
+	v.err.Fields = append(v.err.Fields,
+		fmt.Sprintf("%s %d is not one of the camera's values %v", field, value, allowed))
+}
+
+func (v *validator) fail(format string, args ...interface{}) {
+	v.err.Fields = append(v.err.Fields, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) result(setting string) error {
+	if len(v.err.Fields) == 0 {
+		return nil
+	}
+
+	v.err.Setting = setting
+
+	return &v.err
+}
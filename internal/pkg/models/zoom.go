@@ -0,0 +1,51 @@
+package models
+
+type ZoomFocusPos struct {
+	Pos int `json:"pos"`
+}
+
+// ZoomFocus is the absolute zoom and focus position of the lens
+type ZoomFocus struct {
+	Channel int          `json:"channel"`
+	Focus   ZoomFocusPos `json:"focus"`
+	Zoom    ZoomFocusPos `json:"zoom"`
+}
+
+type ZoomFocusPosRange struct {
+	Pos MinMax `json:"pos"`
+}
+
+// ZoomFocusRange holds the legal zoom and focus positions the camera reported
+type ZoomFocusRange struct {
+	Focus ZoomFocusPosRange `json:"focus"`
+	Zoom  ZoomFocusPosRange `json:"zoom"`
+}
+
+type ZoomFocusSettings struct {
+	ZoomFocus *ZoomFocus
+	Initial   *ZoomFocus
+	Range     *ZoomFocusRange
+}
+
+// Validate checks the zoom and focus positions against the camera's range
+func (r *ZoomFocusRange) Validate(zoomFocus *ZoomFocus) error {
+	v := &validator{}
+
+	v.minMax("zoom", zoomFocus.Zoom.Pos, r.Zoom.Pos)
+	v.minMax("focus", zoomFocus.Focus.Pos, r.Focus.Pos)
+
+	return v.result("zoom and focus")
+}
+
+type AutoFocus struct {
+	Channel int `json:"channel"`
+	Disable int `json:"disable"`
+}
+
+// ZoomFocusPreset is a PTZ preset together with the lens position it was saved with, for cameras whose presets do
+// not restore the zoom and focus.
+type ZoomFocusPreset struct {
+	Preset PtzPreset
+	Zoom   int
+	Focus  int
+}
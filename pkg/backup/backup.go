@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"reflect"
+	"time"
+)
+
+// Backup reads the camera's configuration into a document.
+// Sections the camera refuses, e.g. the motion alarm on a model without one, are listed in Document.Unsupported,
+// any other error aborts the backup.
+func Backup(camera *reolinkapi.Camera) (*Document, error) {
+	handler := camera.RestHandler
+
+	deviceInfo, err := camera.GetDeviceInformation()(handler)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceInfo == nil {
+		return nil, fmt.Errorf("camera did not return its device information")
+	}
+
+	document := &Document{
+		Version:   FORMAT_VERSION,
+		CreatedAt: time.Now().UTC(),
+		Device: Device{
+			Model:           deviceInfo.Model,
+			FirmwareVersion: deviceInfo.FirmwareVersion,
+			HardwareVersion: deviceInfo.HardwareVersion,
+			Name:            deviceInfo.Name,
+		},
+	}
+
+	for _, s := range sections {
+		value, err := s.get(camera)
+
+		if err != nil {
+			if unsupported(err) {
+				document.Unsupported = append(document.Unsupported, s.name)
+				continue
+			}
+
+			return nil, fmt.Errorf("backing up %s: %w", s.name, err)
+		}
+
+		if isNil(value) {
+			document.Unsupported = append(document.Unsupported, s.name)
+			continue
+		}
+
+		s.store(document, value)
+	}
+
+	users, err := camera.GetUsers()(handler)
+
+	if err != nil {
+		if !unsupported(err) {
+			return nil, fmt.Errorf("backing up users: %w", err)
+		}
+
+		document.Unsupported = append(document.Unsupported, "users")
+	}
+
+	for _, user := range users {
+		document.Users = append(document.Users, User{Username: user.Username, Level: user.Level})
+	}
+
+	return document, nil
+}
+
+// the camera answered but refused the command
+func unsupported(err error) bool {
+	var cameraErr *rest.CameraError
+
+	return errors.As(err, &cameraErr)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(v)
+
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,486 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Desired is a desired configuration laid out like a Document, e.g. {"osd": {"osdChannel": {"name": "garden"}}}.
+// Unlike a Document it may be partial: only the fields it holds are compared and changed, every other setting keeps
+// the camera's current value.
+type Desired map[string]interface{}
+
+// DesiredFromDocument turns a whole document into the desired configuration
+func DesiredFromDocument(document *Document) (Desired, error) {
+	data, err := json.Marshal(document)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var desired Desired
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&desired); err != nil {
+		return nil, err
+	}
+
+	delete(desired, "version")
+	delete(desired, "createdAt")
+	delete(desired, "unsupported")
+
+	return desired, nil
+}
+
+// ReadDesired reads a desired configuration written as JSON
+func ReadDesired(r io.Reader) (Desired, error) {
+	var desired Desired
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&desired); err != nil {
+		return nil, fmt.Errorf("reading desired configuration: %w", err)
+	}
+
+	return normalise(desired).(map[string]interface{}), nil
+}
+
+// Change is a single setting that differs, Current is nil when the camera does not have it yet
+type Change struct {
+	Path    string
+	Current interface{}
+	Desired interface{}
+}
+
+func (c Change) String() string {
+	if c.Current == nil {
+		return fmt.Sprintf("+ %s: %s", c.Path, display(c.Path, c.Desired))
+	}
+
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, display(c.Path, c.Current), display(c.Path, c.Desired))
+}
+
+// SectionDiff holds the changes of a section, applying it sends the whole section with the changes made
+type SectionDiff struct {
+	Section string
+	Changes []Change
+	value   interface{}
+}
+
+// Diff is the outcome of Compare.
+// Sections lists the sections that differ, Unchanged the ones that already match and Failed the ones that could not
+// be compared.
+type Diff struct {
+	Sections  []SectionDiff
+	Unchanged []string
+	Failed    []SectionError
+}
+
+// Empty is true when the camera already matches the desired configuration
+func (d *Diff) Empty() bool {
+	return len(d.Sections) == 0
+}
+
+// Changes of every section in order
+func (d *Diff) Changes() []Change {
+	var changes []Change
+
+	for _, s := range d.Sections {
+		changes = append(changes, s.Changes...)
+	}
+
+	return changes
+}
+
+func (d *Diff) String() string {
+	var b strings.Builder
+
+	for _, change := range d.Changes() {
+		fmt.Fprintln(&b, change)
+	}
+
+	for _, f := range d.Failed {
+		fmt.Fprintf(&b, "! %s\n", f.Error())
+	}
+
+	if b.Len() == 0 {
+		return "no changes\n"
+	}
+
+	return b.String()
+}
+
+// Compare reads the camera's current configuration and returns how it differs from the desired one, without
+// changing anything. Pass the diff to Apply to make the changes.
+// Keys of the desired configuration that are not a section, "users" or "device" are refused and fields a section
+// does not have fail that section, catching typos. Passwords are shown as *** in the changes.
+func Compare(camera *reolinkapi.Camera, desired Desired, opts ...OptionRestore) (*Diff, error) {
+	options := newRestoreOptions(opts)
+
+	if err := checkSections(desired, ""); err != nil {
+		return nil, err
+	}
+
+	if err := checkModel(camera, desired, options); err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{}
+
+	if users, ok := desired["users"]; ok && options.selected("users") {
+		compareUsers(camera, users, diff)
+	}
+
+	for _, s := range sections {
+		want := lookup(desired, s.name)
+
+		if want == nil || !options.selected(s.name) {
+			continue
+		}
+
+		changes, value, err := compareSection(camera, s, want)
+
+		switch {
+		case err != nil:
+			diff.Failed = append(diff.Failed, SectionError{Section: s.name, Err: err})
+		case len(changes) == 0:
+			diff.Unchanged = append(diff.Unchanged, s.name)
+		default:
+			diff.Sections = append(diff.Sections, SectionDiff{Section: s.name, Changes: changes, value: value})
+		}
+	}
+
+	return diff, nil
+}
+
+// every key of the desired configuration must be a section or lead to one, prefix is the path of desired
+func checkSections(desired map[string]interface{}, prefix string) error {
+	keys := make([]string, 0, len(desired))
+
+	for key := range desired {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if prefix == "" && (key == "users" || key == "device") {
+			continue
+		}
+
+		path := key
+
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		known, parent := false, false
+
+		for _, s := range sections {
+			known = known || s.name == path
+			parent = parent || strings.HasPrefix(s.name, path+".")
+		}
+
+		if known {
+			continue
+		}
+
+		if !parent {
+			return fmt.Errorf("unknown section %q", path)
+		}
+
+		nested, ok := desired[key].(map[string]interface{})
+
+		if !ok {
+			return fmt.Errorf("%s must hold sections", path)
+		}
+
+		if err := checkSections(nested, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkModel(camera *reolinkapi.Camera, desired Desired, options *restoreOptions) error {
+	device, _ := desired["device"].(map[string]interface{})
+	model, _ := device["model"].(string)
+
+	if options.ignoreModel || model == "" {
+		return nil
+	}
+
+	deviceInfo, err := camera.GetDeviceInformation()(camera.RestHandler)
+
+	if err != nil {
+		return err
+	}
+
+	if deviceInfo == nil {
+		return fmt.Errorf("camera did not return its device information")
+	}
+
+	if model != deviceInfo.Model {
+		return fmt.Errorf("configuration is meant for a %s, camera is a %s", model, deviceInfo.Model)
+	}
+
+	return nil
+}
+
+// the desired value at a dotted section path, nil when it is left out
+func lookup(desired Desired, path string) interface{} {
+	var value interface{} = map[string]interface{}(desired)
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+
+		if !ok {
+			return nil
+		}
+
+		value = m[key]
+	}
+
+	return value
+}
+
+func compareSection(camera *reolinkapi.Camera, s section, want interface{}) ([]Change, interface{}, error) {
+	live, err := s.get(camera)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if isNil(live) {
+		return nil, nil, fmt.Errorf("camera did not return its %s settings", s.name)
+	}
+
+	liveTree, err := tree(live)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// decode the desired fields on top of the camera's values, so left out fields keep them
+	merged, err := json.Marshal(overlay(liveTree, normalise(want)))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := reflect.New(reflect.TypeOf(live).Elem()).Interface()
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(value); err != nil {
+		return nil, nil, err
+	}
+
+	if s.keep != nil {
+		s.keep(live, value)
+	}
+
+	valueTree, err := tree(value)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []Change
+	compareTrees(s.name, liveTree, valueTree, &changes)
+
+	return changes, value, nil
+}
+
+// a value as the generic tree encoding/json decodes into, numbers as json.Number
+func tree(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var t interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// bring every number to json.Number so values from Go, JSON and YAML compare alike
+func normalise(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[k] = normalise(item)
+		}
+		return m
+	case Desired:
+		return normalise(map[string]interface{}(value))
+	case []interface{}:
+		l := make([]interface{}, len(value))
+		for i, item := range value {
+			l[i] = normalise(item)
+		}
+		return l
+	case float64:
+		return json.Number(strconv.FormatFloat(value, 'f', -1, 64))
+	case float32:
+		return json.Number(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	case int, int64, int32, uint, uint64, uint32:
+		return json.Number(fmt.Sprint(value))
+	}
+
+	return v
+}
+
+// lay the desired values over the current ones, objects are merged and anything else is replaced
+func overlay(current interface{}, desired interface{}) interface{} {
+	currentMap, ok := current.(map[string]interface{})
+	desiredMap, isMap := desired.(map[string]interface{})
+
+	if !ok || !isMap {
+		// the camera expresses most switches as 0 and 1, accept true and false for them
+		if b, isBool := desired.(bool); isBool {
+			if _, isNumber := current.(json.Number); isNumber {
+				if b {
+					return json.Number("1")
+				}
+
+				return json.Number("0")
+			}
+		}
+
+		return desired
+	}
+
+	merged := make(map[string]interface{}, len(currentMap))
+
+	for k, v := range currentMap {
+		merged[k] = v
+	}
+
+	for k, v := range desiredMap {
+		merged[k] = overlay(currentMap[k], v)
+	}
+
+	return merged
+}
+
+func compareTrees(path string, current interface{}, desired interface{}, changes *[]Change) {
+	currentMap, currentIsMap := current.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+
+	if currentIsMap && desiredIsMap {
+		keys := make([]string, 0, len(desiredMap))
+
+		for k := range desiredMap {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			compareTrees(path+"."+k, currentMap[k], desiredMap[k], changes)
+		}
+
+		return
+	}
+
+	currentList, currentIsList := current.([]interface{})
+	desiredList, desiredIsList := desired.([]interface{})
+
+	if currentIsList && desiredIsList && len(currentList) == len(desiredList) {
+		for i := range desiredList {
+			compareTrees(fmt.Sprintf("%s[%d]", path, i), currentList[i], desiredList[i], changes)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(current, desired) {
+		*changes = append(*changes, Change{Path: path, Current: current, Desired: desired})
+	}
+}
+
+func display(path string, v interface{}) string {
+	if strings.HasSuffix(strings.ToLower(path), "password") {
+		return "***"
+	}
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// the users are compared by name, only their level can be desired
+func compareUsers(camera *reolinkapi.Camera, want interface{}, diff *Diff) {
+	data, err := json.Marshal(want)
+
+	if err != nil {
+		diff.Failed = append(diff.Failed, SectionError{Section: "users", Err: err})
+		return
+	}
+
+	var users []User
+
+	if err := json.Unmarshal(data, &users); err != nil {
+		diff.Failed = append(diff.Failed, SectionError{Section: "users", Err: err})
+		return
+	}
+
+	live, err := camera.GetUsers()(camera.RestHandler)
+
+	if err != nil {
+		diff.Failed = append(diff.Failed, SectionError{Section: "users", Err: err})
+		return
+	}
+
+	levels := make(map[string]string, len(live))
+
+	for _, user := range live {
+		levels[user.Username] = user.Level
+	}
+
+	var changes []Change
+
+	for _, user := range users {
+		level, exists := levels[user.Username]
+
+		if !exists {
+			changes = append(changes, Change{Path: "users." + user.Username, Desired: user.Level})
+		} else if level != user.Level {
+			changes = append(changes, Change{Path: "users." + user.Username + ".level", Current: level,
+				Desired: user.Level})
+		}
+	}
+
+	if len(changes) == 0 {
+		diff.Unchanged = append(diff.Unchanged, "users")
+		return
+	}
+
+	diff.Sections = append(diff.Sections, SectionDiff{Section: "users", Changes: changes, value: users})
+}
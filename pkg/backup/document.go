@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"io"
+	"time"
+)
+
+// FORMAT_VERSION is the version of the document layout written by Backup.
+// Documents with a newer version are refused by Read, as they may hold settings this version cannot restore.
+const FORMAT_VERSION = 1
+
+// Device identifies the camera a document was taken from
+type Device struct {
+	Model           string `json:"model"`
+	FirmwareVersion string `json:"firmwareVersion"`
+	HardwareVersion string `json:"hardwareVersion"`
+	Name            string `json:"name"`
+}
+
+// Network holds the camera's network services, the addressing (SetLocalLink) is deliberately left out so that a
+// restore never moves the camera to another address.
+type Network struct {
+	Port  *models.NetworkPort  `json:"port,omitempty"`
+	Ntp   *models.NetworkNTP   `json:"ntp,omitempty"`
+	Ddns  *models.NetworkDDNS  `json:"ddns,omitempty"`
+	Email *models.NetworkEmail `json:"email,omitempty"`
+	Ftp   *models.NetworkFTP   `json:"ftp,omitempty"`
+	Push  *models.NetworkPush  `json:"push,omitempty"`
+	Upnp  *models.NetworkUpnp  `json:"upnp,omitempty"`
+	P2p   *models.NetworkP2p   `json:"p2p,omitempty"`
+}
+
+// User is a camera account, passwords are never part of a document
+type User struct {
+	Username string `json:"userName"`
+	Level    string `json:"level"`
+}
+
+// Document is a camera's configuration.
+// Every section is optional, a section left out is not touched by Restore. Unsupported lists the sections the
+// camera refused to return when the backup was taken.
+// DDNS, email and FTP passwords are not stored, Restore keeps the camera's current ones.
+type Document struct {
+	Version     int                 `json:"version"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	Device      Device              `json:"device"`
+	Osd         *models.Osd         `json:"osd,omitempty"`
+	Mask        *models.MaskData    `json:"mask,omitempty"`
+	Encoding    *models.Encoding    `json:"encoding,omitempty"`
+	Recording   *models.Recording   `json:"recording,omitempty"`
+	Image       *models.Image       `json:"image,omitempty"`
+	Isp         *models.Isp         `json:"isp,omitempty"`
+	Alarm       *models.MotionAlarm `json:"alarm,omitempty"`
+	Network     *Network            `json:"network,omitempty"`
+	Users       []User              `json:"users,omitempty"`
+	Unsupported []string            `json:"unsupported,omitempty"`
+}
+
+// Write the document as indented JSON
+func (d *Document) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
+
+// Read a document written by Write
+func Read(r io.Reader) (*Document, error) {
+	var document *Document
+
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("reading backup: %w", err)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("reading backup: empty document")
+	}
+
+	if document.Version < 1 || document.Version > FORMAT_VERSION {
+		return nil, fmt.Errorf("backup version %d is not supported, expected 1 to %d", document.Version,
+			FORMAT_VERSION)
+	}
+
+	return document, nil
+}
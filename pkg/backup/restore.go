@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"strings"
+)
+
+// SectionError is a section Restore could not apply
+type SectionError struct {
+	Section string
+	Err     error
+}
+
+func (se SectionError) Error() string {
+	return fmt.Sprintf("%s: %v", se.Section, se.Err)
+}
+
+// Report is the outcome of Restore, every section of the document is listed exactly once.
+// Changed sections were sent to the camera, Unchanged sections already matched the document.
+type Report struct {
+	Changed   []string
+	Unchanged []string
+	Failed    []SectionError
+}
+
+// Err combines the failed sections into a single error, nil when every section was restored
+func (r *Report) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	var failed []string
+
+	for _, f := range r.Failed {
+		failed = append(failed, f.Error())
+	}
+
+	return fmt.Errorf("restore failed for %d section(s): %s", len(r.Failed), strings.Join(failed, "; "))
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "changed: %s\n", strings.Join(r.Changed, ", "))
+	fmt.Fprintf(&b, "unchanged: %s\n", strings.Join(r.Unchanged, ", "))
+
+	for _, f := range r.Failed {
+		fmt.Fprintf(&b, "failed: %s\n", f.Error())
+	}
+
+	return b.String()
+}
+
+type restoreOptions struct {
+	sections      []string
+	ignoreModel   bool
+	userPasswords map[string]string
+}
+
+type OptionRestore func(*restoreOptions)
+
+// Only restore the named sections, e.g. "osd" or "network.ntp". "network" selects every network section.
+// Default: every section held by the document
+func RestoreOptionSections(names ...string) OptionRestore {
+	return func(r *restoreOptions) {
+		r.sections = append(r.sections, names...)
+	}
+}
+
+// Restore a document taken from another camera model.
+// Default: a document of another model is refused
+func RestoreOptionIgnoreModel() OptionRestore {
+	return func(r *restoreOptions) {
+		r.ignoreModel = true
+	}
+}
+
+// Recreate the user with the password if the camera does not have it.
+// Users missing from the camera are reported as failed otherwise, as a document holds no passwords.
+func RestoreOptionUserPassword(username string, password string) OptionRestore {
+	return func(r *restoreOptions) {
+		r.userPasswords[username] = password
+	}
+}
+
+func (r *restoreOptions) selected(name string) bool {
+	if len(r.sections) == 0 {
+		return true
+	}
+
+	for _, s := range r.sections {
+		if s == name || strings.HasPrefix(name, s+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newRestoreOptions(opts []OptionRestore) *restoreOptions {
+	options := &restoreOptions{
+		userPasswords: map[string]string{},
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	return options
+}
+
+// Restore sends the document's configuration to the camera.
+// Each section is compared with the camera's current value first and only sent when it differs. A section that fails
+// does not stop the others, check the report. An error is only returned when the document is meant for another model
+// or the camera's configuration could not be compared with it, in which case nothing was restored.
+// The network ports are restored last, a changed HTTP(S) port requires a new camera to reach it afterwards.
+func Restore(camera *reolinkapi.Camera, document *Document, opts ...OptionRestore) (*Report, error) {
+	if document == nil {
+		return nil, fmt.Errorf("backup document is required")
+	}
+
+	desired, err := DesiredFromDocument(document)
+
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := Compare(camera, desired, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Apply(camera, diff, opts...), nil
+}
+
+// Apply sends the sections of the diff that differ to the camera, see Compare.
+// Sections that could not be compared are reported as failed. Missing users are only created when their password
+// is given with RestoreOptionUserPassword.
+func Apply(camera *reolinkapi.Camera, diff *Diff, opts ...OptionRestore) *Report {
+	options := newRestoreOptions(opts)
+
+	report := &Report{
+		Unchanged: append([]string(nil), diff.Unchanged...),
+		Failed:    append([]SectionError(nil), diff.Failed...),
+	}
+
+	for _, sectionDiff := range diff.Sections {
+		var err error
+
+		if sectionDiff.Section == "users" {
+			err = applyUsers(camera, sectionDiff.value.([]User), options)
+		} else {
+			for _, s := range sections {
+				if s.name == sectionDiff.Section {
+					err = s.set(camera, sectionDiff.value)
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			report.Failed = append(report.Failed, SectionError{Section: sectionDiff.Section, Err: err})
+		} else {
+			report.Changed = append(report.Changed, sectionDiff.Section)
+		}
+	}
+
+	return report
+}
+
+// users cannot be changed through the API beyond being added, missing users are only recreated with a password
+func applyUsers(camera *reolinkapi.Camera, users []User, options *restoreOptions) error {
+	live, err := camera.GetUsers()(camera.RestHandler)
+
+	if err != nil {
+		return err
+	}
+
+	levels := make(map[string]string, len(live))
+
+	for _, user := range live {
+		levels[user.Username] = user.Level
+	}
+
+	var problems []string
+
+	for _, user := range users {
+		level, exists := levels[user.Username]
+
+		if exists {
+			if level != user.Level {
+				problems = append(problems, fmt.Sprintf("%s is %s instead of %s and the level cannot be changed",
+					user.Username, level, user.Level))
+			}
+
+			continue
+		}
+
+		password, ok := options.userPasswords[user.Username]
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is missing and no password was given to recreate it",
+				user.Username))
+			continue
+		}
+
+		userLevel := enum.USER_LEVEL_GUEST
+
+		if user.Level == enum.USER_LEVEL_ADMIN.Value() {
+			userLevel = enum.USER_LEVEL_ADMIN
+		}
+
+		if _, err := camera.AddUser(user.Username, password, userLevel)(camera.RestHandler); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", user.Username, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
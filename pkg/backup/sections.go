@@ -0,0 +1,321 @@
+package backup
+
+import (
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+)
+
+// section ties a part of the document to the getter and setter of the camera
+type section struct {
+	name string
+	// read the camera's current value
+	get func(camera *reolinkapi.Camera) (interface{}, error)
+	// the document's value, nil when the document leaves the section out
+	value func(d *Document) interface{}
+	// store a value read from the camera in the document
+	store func(d *Document, v interface{})
+	// fill in what the document does not hold from the camera's value, e.g. passwords
+	keep func(live interface{}, desired interface{})
+	// send the document's value to the camera
+	set func(camera *reolinkapi.Camera, v interface{}) error
+}
+
+func network(d *Document) *Network {
+	if d.Network == nil {
+		d.Network = &Network{}
+	}
+
+	return d.Network
+}
+
+func setResult(_ bool, err error) error {
+	return err
+}
+
+// sections in the order they are restored, the network ports come last as changing the HTTP port cuts off the
+// connection used for the remaining sections
+var sections = []section{
+	{
+		name: "image",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			settings, err := c.GetImageSettings()(c.RestHandler)
+			if err != nil {
+				return nil, err
+			}
+			return settings.Image, nil
+		},
+		value: func(d *Document) interface{} {
+			if d.Image == nil {
+				return nil
+			}
+			return d.Image
+		},
+		store: func(d *Document, v interface{}) { d.Image = v.(*models.Image) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetImageSettings(api.ImageOptionBase(v.(*models.Image)))(c.RestHandler))
+		},
+	},
+	{
+		name: "isp",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			settings, err := c.GetAdvanceImageSettings()(c.RestHandler)
+			if err != nil {
+				return nil, err
+			}
+			return settings.Isp, nil
+		},
+		value: func(d *Document) interface{} {
+			if d.Isp == nil {
+				return nil
+			}
+			return d.Isp
+		},
+		store: func(d *Document, v interface{}) { d.Isp = v.(*models.Isp) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetAdvanceImageSettings(api.ImageAdvancedOptionBase(v.(*models.Isp)))(c.RestHandler))
+		},
+	},
+	{
+		name: "osd",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetOSD()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Osd == nil {
+				return nil
+			}
+			return d.Osd
+		},
+		store: func(d *Document, v interface{}) { d.Osd = v.(*models.Osd) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetOSD(options.WithOsdOptionBase(v.(*models.Osd)))(c.RestHandler))
+		},
+	},
+	{
+		name: "mask",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetMask()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Mask == nil {
+				return nil
+			}
+			return d.Mask
+		},
+		store: func(d *Document, v interface{}) { d.Mask = v.(*models.MaskData) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetMask(v.(*models.MaskData))(c.RestHandler))
+		},
+	},
+	{
+		name: "encoding",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetRecordingEncoding()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Encoding == nil {
+				return nil
+			}
+			return d.Encoding
+		},
+		store: func(d *Document, v interface{}) { d.Encoding = v.(*models.Encoding) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetRecordingEncoding(api.RecordingEncodingOptionBase(v.(*models.Encoding)))(
+				c.RestHandler))
+		},
+	},
+	{
+		name: "recording",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetRecordingAdvanced()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Recording == nil {
+				return nil
+			}
+			return d.Recording
+		},
+		store: func(d *Document, v interface{}) { d.Recording = v.(*models.Recording) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetRecordingAdvanced(v.(*models.Recording))(c.RestHandler))
+		},
+	},
+	{
+		name: "alarm",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetMotionAlarm()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Alarm == nil {
+				return nil
+			}
+			return d.Alarm
+		},
+		store: func(d *Document, v interface{}) { d.Alarm = v.(*models.MotionAlarm) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetMotionAlarm(v.(*models.MotionAlarm))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.ntp",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkNTP()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Ntp == nil {
+				return nil
+			}
+			return d.Network.Ntp
+		},
+		store: func(d *Document, v interface{}) { network(d).Ntp = v.(*models.NetworkNTP) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetNtp(options.WithNtpOptionBase(v.(*models.NetworkNTP)))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.ddns",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkDDNS()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Ddns == nil {
+				return nil
+			}
+			return d.Network.Ddns
+		},
+		store: func(d *Document, v interface{}) {
+			ddns := *v.(*models.NetworkDDNS)
+			ddns.Password = ""
+			network(d).Ddns = &ddns
+		},
+		keep: func(live interface{}, desired interface{}) {
+			if d := desired.(*models.NetworkDDNS); d.Password == "" {
+				d.Password = live.(*models.NetworkDDNS).Password
+			}
+		},
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetDdns(options.WithDdnsOptionBase(v.(*models.NetworkDDNS)))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.email",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkEmail()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Email == nil {
+				return nil
+			}
+			return d.Network.Email
+		},
+		store: func(d *Document, v interface{}) {
+			email := *v.(*models.NetworkEmail)
+			email.Password = ""
+			network(d).Email = &email
+		},
+		keep: func(live interface{}, desired interface{}) {
+			if d := desired.(*models.NetworkEmail); d.Password == "" {
+				d.Password = live.(*models.NetworkEmail).Password
+			}
+		},
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetEmail(options.WithEmailOptionBase(v.(*models.NetworkEmail)))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.ftp",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkFTP()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Ftp == nil {
+				return nil
+			}
+			return d.Network.Ftp
+		},
+		store: func(d *Document, v interface{}) {
+			ftp := *v.(*models.NetworkFTP)
+			ftp.Password = ""
+			network(d).Ftp = &ftp
+		},
+		keep: func(live interface{}, desired interface{}) {
+			if d := desired.(*models.NetworkFTP); d.Password == "" {
+				d.Password = live.(*models.NetworkFTP).Password
+			}
+		},
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetFtp(options.WithFtpOptionBase(v.(*models.NetworkFTP)))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.push",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkPush()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Push == nil {
+				return nil
+			}
+			return d.Network.Push
+		},
+		store: func(d *Document, v interface{}) { network(d).Push = v.(*models.NetworkPush) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetPush(options.WithPushOptionBase(v.(*models.NetworkPush)))(c.RestHandler))
+		},
+	},
+	{
+		name: "network.upnp",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetUpnp()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Upnp == nil {
+				return nil
+			}
+			return d.Network.Upnp
+		},
+		store: func(d *Document, v interface{}) { network(d).Upnp = v.(*models.NetworkUpnp) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetUpnp(v.(*models.NetworkUpnp).Enable)(c.RestHandler))
+		},
+	},
+	{
+		name: "network.p2p",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetP2p()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.P2p == nil {
+				return nil
+			}
+			return d.Network.P2p
+		},
+		store: func(d *Document, v interface{}) { network(d).P2p = v.(*models.NetworkP2p) },
+		// the uid belongs to the camera, only the switch is restored
+		keep: func(live interface{}, desired interface{}) {
+			desired.(*models.NetworkP2p).Uid = live.(*models.NetworkP2p).Uid
+		},
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetP2p(v.(*models.NetworkP2p).Enable)(c.RestHandler))
+		},
+	},
+	{
+		name: "network.port",
+		get: func(c *reolinkapi.Camera) (interface{}, error) {
+			return c.GetNetworkPort()(c.RestHandler)
+		},
+		value: func(d *Document) interface{} {
+			if d.Network == nil || d.Network.Port == nil {
+				return nil
+			}
+			return d.Network.Port
+		},
+		store: func(d *Document, v interface{}) { network(d).Port = v.(*models.NetworkPort) },
+		set: func(c *reolinkapi.Camera, v interface{}) error {
+			return setResult(c.SetNetworkPort(options.WithNetworkPortOptionBase(v.(*models.NetworkPort)))(
+				c.RestHandler))
+		},
+	},
+}
@@ -25,3 +25,38 @@ const (
 func (t Toggle) String() string {
 	return [...]string{"disabled", "enabled"}[t]
 }
+
+type OsdDateFormat uint
+
+const (
+	DATE_FORMAT_DD_MM_YYYY OsdDateFormat = iota
+	DATE_FORMAT_MM_DD_YYYY
+	DATE_FORMAT_YYYY_MM_DD
+)
+
+func (df OsdDateFormat) Value() string {
+	return []string{"DD/MM/YYYY", "MM/DD/YYYY", "YYYY/MM/DD"}[df]
+}
+
+type OsdHourFormat uint
+
+const (
+	HOUR_FORMAT_24 OsdHourFormat = iota
+	HOUR_FORMAT_12
+)
+
+func (hf OsdHourFormat) Value() int {
+	return []int{0, 1}[hf]
+}
+
+type OsdFontSize uint
+
+const (
+	FONT_SIZE_SMALL OsdFontSize = iota
+	FONT_SIZE_MEDIUM
+	FONT_SIZE_LARGE
+)
+
+func (fs OsdFontSize) Value() string {
+	return []string{"Small", "Middle", "Large"}[fs]
+}
@@ -1,17 +1,40 @@
 package enum
 
+import "fmt"
+
 // === Image Settings enums ===
-// TODO: still need to finalise this
+
+// find the index of value in values, used to map camera strings back onto the enums below
+func indexOf(values []string, value string, name string) (uint, error) {
+	for i, v := range values {
+		if v == value {
+			return uint(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown %s value %q", name, value)
+}
 
 // ===
 type AntiFlicker uint
 
 const (
 	OUTDOOR AntiFlicker = iota
+	ANTI_FLICKER_50HZ
+	ANTI_FLICKER_60HZ
+	ANTI_FLICKER_OFF
 )
 
+var antiFlickerValues = []string{"Outdoor", "50HZ", "60HZ", "Off"}
+
 func (i AntiFlicker) Value() string {
-	return []string{"Outdoor"}[i]
+	return antiFlickerValues[i]
+}
+
+// AntiFlickerFromValue returns the AntiFlicker matching the camera's value
+func AntiFlickerFromValue(value string) (AntiFlicker, error) {
+	i, err := indexOf(antiFlickerValues, value, "anti flicker")
+	return AntiFlicker(i), err
 }
 
 // ===
@@ -20,10 +43,21 @@ type Exposure uint
 
 const (
 	EXPOSURE_AUTO Exposure = iota
+	EXPOSURE_LOW_NOISE
+	EXPOSURE_ANTI_SMEARING
+	EXPOSURE_MANUAL
 )
 
+var exposureValues = []string{"Auto", "LowNoise", "Anti-Smearing", "Manual"}
+
 func (i Exposure) Value() string {
-	return []string{"Auto"}[i]
+	return exposureValues[i]
+}
+
+// ExposureFromValue returns the Exposure matching the camera's value
+func ExposureFromValue(value string) (Exposure, error) {
+	i, err := indexOf(exposureValues, value, "exposure")
+	return Exposure(i), err
 }
 
 // ===
@@ -32,10 +66,20 @@ type Backlight uint
 
 const (
 	DYNAMIC_RANGE_CONTROL Backlight = iota
+	BACK_LIGHT_CONTROL
+	BACK_LIGHT_OFF
 )
 
+var backlightValues = []string{"DynamicRangeControl", "BackLightControl", "Off"}
+
 func (b Backlight) Value() string {
-	return []string{"DynamicRangeControl"}[b]
+	return backlightValues[b]
+}
+
+// BacklightFromValue returns the Backlight matching the camera's value
+func BacklightFromValue(value string) (Backlight, error) {
+	i, err := indexOf(backlightValues, value, "backlight")
+	return Backlight(i), err
 }
 
 // ==
@@ -44,20 +88,39 @@ type WhiteBalance uint
 
 const (
 	WHITE_BALANCE_AUTO WhiteBalance = iota
+	WHITE_BALANCE_MANUAL
 )
 
+var whiteBalanceValues = []string{"Auto", "Manual"}
+
 func (wb WhiteBalance) Value() string {
-	return []string{"Auto"}[wb]
+	return whiteBalanceValues[wb]
+}
+
+// WhiteBalanceFromValue returns the WhiteBalance matching the camera's value
+func WhiteBalanceFromValue(value string) (WhiteBalance, error) {
+	i, err := indexOf(whiteBalanceValues, value, "white balance")
+	return WhiteBalance(i), err
 }
 
 type DayNight uint
 
 const (
 	DAY_NIGHT_AUTO DayNight = iota
+	DAY_NIGHT_COLOR
+	DAY_NIGHT_BLACK_WHITE
 )
 
+var dayNightValues = []string{"Auto", "Color", "Black&White"}
+
 func (dn DayNight) Value() string {
-	return []string{"Auto"}[dn]
+	return dayNightValues[dn]
+}
+
+// DayNightFromValue returns the DayNight matching the camera's value
+func DayNightFromValue(value string) (DayNight, error) {
+	i, err := indexOf(dayNightValues, value, "day night")
+	return DayNight(i), err
 }
 
 type Rotation uint
@@ -70,5 +133,14 @@ const (
 )
 
 func (r Rotation) Value() int {
-	return []int{1}[r]
+	return []int{0, 1, 2, 3}[r]
+}
+
+// RotationFromValue returns the Rotation matching the camera's value
+func RotationFromValue(value int) (Rotation, error) {
+	if value < 0 || value > int(ROTATION_270) {
+		return ROTATION_0, fmt.Errorf("unknown rotation value %d", value)
+	}
+
+	return Rotation(value), nil
 }
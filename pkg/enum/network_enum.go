@@ -0,0 +1,13 @@
+package enum
+
+// LinkType is how the camera gets its wired (LAN) address
+type LinkType uint
+
+const (
+	LINK_TYPE_DHCP LinkType = iota
+	LINK_TYPE_STATIC
+)
+
+func (lt LinkType) Value() string {
+	return []string{"DHCP", "Static"}[lt]
+}
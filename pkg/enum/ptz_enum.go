@@ -0,0 +1,46 @@
+package enum
+
+import "fmt"
+
+type PtzDirection uint
+
+const (
+	PTZ_LEFT PtzDirection = iota
+	PTZ_RIGHT
+	PTZ_UP
+	PTZ_DOWN
+	PTZ_LEFT_UP
+	PTZ_LEFT_DOWN
+	PTZ_RIGHT_UP
+	PTZ_RIGHT_DOWN
+)
+
+func (pd PtzDirection) Value() string {
+	return []string{"Left", "Right", "Up", "Down", "LeftUp", "LeftDown", "RightUp", "RightDown"}[pd]
+}
+
+// PtzCheckState is the progress of the PTZ self-check (calibration)
+type PtzCheckState uint
+
+const (
+	PTZ_CHECK_IDLE PtzCheckState = iota
+	PTZ_CHECK_RUNNING
+	PTZ_CHECK_FINISHED
+)
+
+func (ps PtzCheckState) Value() int {
+	return []int{0, 1, 2}[ps]
+}
+
+func (ps PtzCheckState) String() string {
+	return []string{"idle", "running", "finished"}[ps]
+}
+
+// PtzCheckStateFromValue returns the PtzCheckState matching the camera's value
+func PtzCheckStateFromValue(value int) (PtzCheckState, error) {
+	if value < 0 || value > int(PTZ_CHECK_FINISHED) {
+		return PTZ_CHECK_IDLE, fmt.Errorf("unknown ptz check state %d", value)
+	}
+
+	return PtzCheckState(value), nil
+}
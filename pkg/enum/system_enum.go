@@ -0,0 +1,35 @@
+package enum
+
+import "time"
+
+// MaintenanceDay is the day the camera reboots itself for maintenance
+type MaintenanceDay uint
+
+const (
+	MAINTENANCE_EVERYDAY MaintenanceDay = iota
+	MAINTENANCE_SUNDAY
+	MAINTENANCE_MONDAY
+	MAINTENANCE_TUESDAY
+	MAINTENANCE_WEDNESDAY
+	MAINTENANCE_THURSDAY
+	MAINTENANCE_FRIDAY
+	MAINTENANCE_SATURDAY
+)
+
+var maintenanceDayValues = []string{"Everyday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday",
+	"Saturday"}
+
+func (md MaintenanceDay) Value() string {
+	return maintenanceDayValues[md]
+}
+
+// MaintenanceDayFromValue returns the MaintenanceDay matching the camera's value
+func MaintenanceDayFromValue(value string) (MaintenanceDay, error) {
+	i, err := indexOf(maintenanceDayValues, value, "maintenance day")
+	return MaintenanceDay(i), err
+}
+
+// MaintenanceDayFromWeekday returns the MaintenanceDay of a time.Weekday
+func MaintenanceDayFromWeekday(weekday time.Weekday) MaintenanceDay {
+	return MaintenanceDay(weekday) + MAINTENANCE_SUNDAY
+}
@@ -0,0 +1,274 @@
+package firmware
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"golang.org/x/net/context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Stage is the step an upgrade is in, reported to the progress callback
+type Stage uint
+
+const (
+	STAGE_UPLOAD Stage = iota
+	STAGE_INSTALL
+	STAGE_REBOOT
+	STAGE_VERIFY
+)
+
+func (s Stage) String() string {
+	return []string{"upload", "install", "reboot", "verify"}[s]
+}
+
+// Result describes the camera's firmware before and after Upgrade.
+// Upgraded is false when the camera already ran the target version and nothing was sent.
+type Result struct {
+	PreviousVersion string
+	Version         string
+	Upgraded        bool
+}
+
+type upgradeOptions struct {
+	targetVersion string
+	restoreConfig bool
+	pollInterval  time.Duration
+	rebootTimeout time.Duration
+	progress      func(stage Stage, percent int)
+}
+
+type OptionUpgrade func(*upgradeOptions)
+
+// Set the firmware version the image contains, e.g. v3.0.0.660_21110804.
+// Cameras already running it are skipped and the upgrade only succeeds once the camera reports it.
+// Without it any version change counts as success.
+func UpgradeOptionTargetVersion(version string) OptionUpgrade {
+	return func(u *upgradeOptions) {
+		u.targetVersion = version
+	}
+}
+
+// Reset the camera's settings to their defaults as part of the upgrade
+// Default: false
+func UpgradeOptionRestoreConfig(restore bool) OptionUpgrade {
+	return func(u *upgradeOptions) {
+		u.restoreConfig = restore
+	}
+}
+
+// Set how often the installation progress and the rebooting camera are polled
+// Default: 2 seconds
+func UpgradeOptionPollInterval(interval time.Duration) OptionUpgrade {
+	return func(u *upgradeOptions) {
+		u.pollInterval = interval
+	}
+}
+
+// Set how long the camera may take to come back with a new version once it installed the image, ctx can only
+// shorten it
+// Default: 10 minutes
+func UpgradeOptionTimeout(timeout time.Duration) OptionUpgrade {
+	return func(u *upgradeOptions) {
+		u.rebootTimeout = timeout
+	}
+}
+
+// Get notified of the upgrade's progress, percent is only meaningful for STAGE_INSTALL
+func UpgradeOptionProgress(progress func(stage Stage, percent int)) OptionUpgrade {
+	return func(u *upgradeOptions) {
+		u.progress = progress
+	}
+}
+
+// UpgradeFile upgrades the camera with the firmware image (.pak) at path, see Upgrade
+func UpgradeFile(ctx context.Context, camera *reolinkapi.Camera, path string, opts ...OptionUpgrade) (*Result,
+	error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return Upgrade(ctx, camera, filepath.Base(path), file, opts...)
+}
+
+// Upgrade installs the firmware image read from firmware on the camera.
+// The current version is checked first, then the image is uploaded, the installation followed until the camera
+// reboots, after which the camera is logged into again and its new version verified.
+// Cancel ctx, or give it a deadline, to bound the whole upgrade. Beware that cancelling once the image is uploaded
+// only stops waiting, the camera carries on installing it. Waiting for the camera to come back with the new version
+// is also bounded by UpgradeOptionTimeout.
+func Upgrade(ctx context.Context, camera *reolinkapi.Camera, fileName string, firmware io.Reader,
+	opts ...OptionUpgrade) (*Result, error) {
+	options := &upgradeOptions{
+		pollInterval:  2 * time.Second,
+		rebootTimeout: 10 * time.Minute,
+		progress:      func(Stage, int) {},
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	handler := camera.RestHandler
+
+	deviceInfo, err := camera.GetDeviceInformation()(handler)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceInfo == nil {
+		return nil, fmt.Errorf("camera did not return its device information")
+	}
+
+	result := &Result{
+		PreviousVersion: deviceInfo.FirmwareVersion,
+		Version:         deviceInfo.FirmwareVersion,
+	}
+
+	if options.targetVersion != "" && deviceInfo.FirmwareVersion == options.targetVersion {
+		return result, nil
+	}
+
+	options.progress(STAGE_UPLOAD, 0)
+
+	if _, err := camera.UpgradePrepare(fileName, options.restoreConfig)(handler); err != nil {
+		return nil, err
+	}
+
+	if _, err := camera.UploadFirmware(ctx, fileName, firmware)(handler); err != nil {
+		return nil, fmt.Errorf("uploading %s: %w", fileName, err)
+	}
+
+	options.progress(STAGE_UPLOAD, 100)
+
+	rebooting, err := waitForInstall(ctx, camera, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options.progress(STAGE_REBOOT, 0)
+
+	version, err := waitForVersion(ctx, camera, result.PreviousVersion, rebooting, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options.progress(STAGE_VERIFY, 100)
+
+	result.Version = version
+	result.Upgraded = true
+
+	return result, nil
+}
+
+// wait for the camera to finish installing, a camera that stops answering is taken to be rebooting into the image.
+// Whether it was installed is left to waitForVersion.
+func waitForInstall(ctx context.Context, camera *reolinkapi.Camera, options *upgradeOptions) (bool, error) {
+	ticker := time.NewTicker(options.pollInterval)
+	defer ticker.Stop()
+
+	percent := 0
+
+	for {
+		status, err := camera.GetUpgradeStatus()(camera.RestHandler)
+
+		if err != nil {
+			var urlErr *url.Error
+
+			if percent > 0 || errors.As(err, &urlErr) {
+				return true, nil
+			}
+
+			return false, fmt.Errorf("reading the upgrade status: %w", err)
+		}
+
+		if status.Code != 0 {
+			return false, fmt.Errorf("camera failed to install the firmware at %d%%, code %d", status.Percent, status.Code)
+		}
+
+		percent = status.Percent
+		options.progress(STAGE_INSTALL, percent)
+
+		if percent >= 100 {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("firmware installation still at %d%%: %w", percent, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// log in again until the camera is back and reports a new version, errors while it reboots are expected.
+// A camera that was down and comes back with the previous version did not install the image.
+func waitForVersion(ctx context.Context, camera *reolinkapi.Camera, previous string, down bool,
+	options *upgradeOptions) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, options.rebootTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(options.pollInterval)
+	defer ticker.Stop()
+
+	version := previous
+	var lastErr error
+
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return "", fmt.Errorf("camera did not come back after the upgrade (last error: %v): %w",
+					lastErr, ctx.Err())
+			}
+
+			return "", fmt.Errorf("camera still reports firmware %s after the upgrade: %w", version, ctx.Err())
+		case <-ticker.C:
+		}
+
+		if _, err := camera.Login()(camera.RestHandler); err != nil {
+			lastErr = err
+			down = true
+			continue
+		}
+
+		deviceInfo, err := camera.GetDeviceInformation()(camera.RestHandler)
+
+		if err != nil || deviceInfo == nil {
+			lastErr = err
+			down = true
+			continue
+		}
+
+		lastErr = nil
+		version = deviceInfo.FirmwareVersion
+
+		if version == previous {
+			if down {
+				return "", fmt.Errorf("camera rebooted but still reports firmware %s, the image was not installed",
+					version)
+			}
+
+			continue
+		}
+
+		options.progress(STAGE_VERIFY, 0)
+
+		if options.targetVersion != "" && version != options.targetVersion {
+			return "", fmt.Errorf("camera came back with firmware %s instead of %s", version, options.targetVersion)
+		}
+
+		return version, nil
+	}
+}
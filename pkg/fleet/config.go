@@ -0,0 +1,393 @@
+package fleet
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/backup"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Credential tells where a camera's login is found, passwords never live in the fleet file itself
+type Credential struct {
+	Username     string `yaml:"username"`
+	PasswordEnv  string `yaml:"passwordEnv"`
+	PasswordFile string `yaml:"passwordFile"`
+}
+
+// Camera is a camera of the fleet and the configuration it should have
+type Camera struct {
+	Name        string
+	Host        string
+	Credentials string
+	Desired     backup.Desired
+	// passwords used to create users that are missing, by user name
+	userPasswords map[string]string
+}
+
+// Fleet is a declarative description of many cameras, loaded with Load or LoadFile.
+//
+//	credentials:
+//	  office:
+//	    username: admin
+//	    passwordEnv: OFFICE_CAMERA_PASSWORD
+//	defaults:
+//	  network:
+//	    ntp: {enable: true, server: pool.ntp.org}
+//	cameras:
+//	  - name: entrance
+//	    host: 192.168.1.20
+//	    credentials: office
+//	    osd: {osdChannel: {name: Entrance}}
+//	    encoding: {mainStream: {frameRate: 25}}
+//	    network: {port: {rtspPort: 554}}
+//	    users: [{userName: viewer, level: guest, passwordEnv: VIEWER_PASSWORD}]
+//	    schedules: {recording: always, email: "mon-fri 08-18"}
+//
+// The settings of a camera are laid out like a backup.Document and may be partial, settings left out are not
+// touched. Defaults are merged under every camera, a camera's own settings win.
+// Schedules are written as "always", "never" or a list of "<days> <from>-<to>" entries, see ParseSchedule, for
+// recording, alarm, email, ftp and push.
+type Fleet struct {
+	Credentials map[string]Credential
+	Cameras     []*Camera
+}
+
+// the settings a camera may hold besides name, host, credentials and schedules
+var settingKeys = map[string]bool{
+	"device": true, "osd": true, "mask": true, "encoding": true, "recording": true, "image": true, "isp": true,
+	"alarm": true, "network": true, "users": true,
+}
+
+// where each schedule goes in the desired configuration
+var schedulePaths = map[string][]string{
+	"recording": {"recording", "schedule"},
+	"alarm":     {"alarm", "schedule"},
+	"email":     {"network", "email", "schedule"},
+	"ftp":       {"network", "ftp", "schedule"},
+	"push":      {"network", "push", "schedule"},
+}
+
+type fleetFile struct {
+	Credentials map[string]Credential    `yaml:"credentials"`
+	Defaults    map[string]interface{}   `yaml:"defaults"`
+	Cameras     []map[string]interface{} `yaml:"cameras"`
+}
+
+// LoadFile loads the fleet described in the YAML file at path
+func LoadFile(path string) (*Fleet, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Load loads a fleet described in YAML, see Fleet for the layout
+func Load(r io.Reader) (*Fleet, error) {
+	var f fleetFile
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&f); err != nil {
+		return nil, fmt.Errorf("reading fleet: %w", err)
+	}
+
+	if err := checkSettings("defaults", f.Defaults); err != nil {
+		return nil, err
+	}
+
+	fleet := &Fleet{Credentials: f.Credentials}
+	names := map[string]bool{}
+
+	for i, c := range f.Cameras {
+		camera, err := loadCamera(c, f.Defaults)
+
+		if err != nil {
+			return nil, fmt.Errorf("camera %d: %w", i+1, err)
+		}
+
+		if names[camera.Name] {
+			return nil, fmt.Errorf("camera %q is listed twice", camera.Name)
+		}
+
+		names[camera.Name] = true
+
+		if camera.Credentials != "" {
+			if _, ok := f.Credentials[camera.Credentials]; !ok {
+				return nil, fmt.Errorf("camera %q: credentials %q are not defined", camera.Name,
+					camera.Credentials)
+			}
+		}
+
+		fleet.Cameras = append(fleet.Cameras, camera)
+	}
+
+	return fleet, nil
+}
+
+func loadCamera(c map[string]interface{}, defaults map[string]interface{}) (*Camera, error) {
+	camera := &Camera{
+		Desired:       backup.Desired{},
+		userPasswords: map[string]string{},
+	}
+
+	settings := map[string]interface{}{}
+
+	for key, value := range c {
+		var ok bool
+
+		switch key {
+		case "name":
+			camera.Name, ok = value.(string)
+		case "host":
+			camera.Host, ok = value.(string)
+		case "credentials":
+			camera.Credentials, ok = value.(string)
+		case "schedules":
+			ok = true
+		default:
+			settings[key] = value
+			ok = true
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+
+	if camera.Name == "" {
+		camera.Name = camera.Host
+	}
+
+	if camera.Host == "" {
+		return nil, fmt.Errorf("camera %q has no host", camera.Name)
+	}
+
+	if err := checkSettings(camera.Name, settings); err != nil {
+		return nil, err
+	}
+
+	for key, value := range merge(defaults, settings) {
+		camera.Desired[key] = value
+	}
+
+	if err := camera.loadUsers(); err != nil {
+		return nil, err
+	}
+
+	if schedules, ok := c["schedules"]; ok {
+		if err := camera.loadSchedules(schedules); err != nil {
+			return nil, err
+		}
+	}
+
+	return camera, nil
+}
+
+func checkSettings(name string, settings map[string]interface{}) error {
+	for key := range settings {
+		if !settingKeys[key] {
+			return fmt.Errorf("%s: unknown setting %q", name, key)
+		}
+	}
+
+	return nil
+}
+
+// merge the camera's settings over the defaults, maps are merged and anything else is replaced.
+// The maps of base are copied, the camera's settings are written into the result and must not reach the defaults.
+func merge(base map[string]interface{}, over map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(over))
+
+	for k, v := range base {
+		if baseMap, ok := v.(map[string]interface{}); ok {
+			v = merge(baseMap, nil)
+		}
+
+		merged[k] = v
+	}
+
+	for k, v := range over {
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		overMap, overIsMap := v.(map[string]interface{})
+
+		if baseIsMap && overIsMap {
+			merged[k] = merge(baseMap, overMap)
+		} else {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// take the passwords of missing users out of the desired configuration, they are only needed to create them
+func (c *Camera) loadUsers() error {
+	users, ok := c.Desired["users"]
+
+	if !ok {
+		return nil
+	}
+
+	list, ok := users.([]interface{})
+
+	if !ok {
+		return fmt.Errorf("camera %q: users must be a list", c.Name)
+	}
+
+	stripped := make([]interface{}, 0, len(list))
+
+	for _, u := range list {
+		user, ok := u.(map[string]interface{})
+
+		if !ok {
+			return fmt.Errorf("camera %q: every user needs a userName and a level", c.Name)
+		}
+
+		name, _ := user["userName"].(string)
+		level, _ := user["level"].(string)
+
+		if name == "" || level == "" {
+			return fmt.Errorf("camera %q: every user needs a userName and a level", c.Name)
+		}
+
+		if env, ok := user["passwordEnv"].(string); ok {
+			password, found := os.LookupEnv(env)
+
+			if !found {
+				return fmt.Errorf("camera %q: user %s: environment variable %s is not set", c.Name, name, env)
+			}
+
+			c.userPasswords[name] = password
+		}
+
+		stripped = append(stripped, map[string]interface{}{"userName": name, "level": level})
+	}
+
+	c.Desired["users"] = stripped
+
+	return nil
+}
+
+func (c *Camera) loadSchedules(schedules interface{}) error {
+	m, ok := schedules.(map[string]interface{})
+
+	if !ok {
+		return fmt.Errorf("camera %q: schedules must map a feature to its schedule", c.Name)
+	}
+
+	features := make([]string, 0, len(m))
+
+	for feature := range m {
+		features = append(features, feature)
+	}
+
+	sort.Strings(features)
+
+	for _, feature := range features {
+		path, ok := schedulePaths[feature]
+
+		if !ok {
+			return fmt.Errorf("camera %q: unknown schedule %q", c.Name, feature)
+		}
+
+		var specs []string
+
+		switch value := m[feature].(type) {
+		case string:
+			specs = []string{value}
+		case []interface{}:
+			for _, v := range value {
+				spec, ok := v.(string)
+
+				if !ok {
+					return fmt.Errorf("camera %q: schedule %s: entries must be strings", c.Name, feature)
+				}
+
+				specs = append(specs, spec)
+			}
+		default:
+			return fmt.Errorf("camera %q: schedule %s must be a string or a list", c.Name, feature)
+		}
+
+		table, err := ParseSchedule(specs...)
+
+		if err != nil {
+			return fmt.Errorf("camera %q: schedule %s: %w", c.Name, feature, err)
+		}
+
+		schedule := map[string]interface{}{"table": table}
+
+		// the alarm is armed through its own switch, every other schedule has one
+		if feature != "alarm" {
+			schedule["enable"] = strings.Contains(table, "1")
+		}
+
+		c.set(path, schedule)
+	}
+
+	return nil
+}
+
+// set a value in the desired configuration, creating the maps on the way
+func (c *Camera) set(path []string, value interface{}) {
+	m := map[string]interface{}(c.Desired)
+
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+
+		m = next
+	}
+
+	m[path[len(path)-1]] = value
+}
+
+// resolve the camera's login through its credentials
+func (f *Fleet) login(camera *Camera) (string, string, error) {
+	if camera.Credentials == "" {
+		return "admin", "", nil
+	}
+
+	credential := f.Credentials[camera.Credentials]
+	username := credential.Username
+
+	if username == "" {
+		username = "admin"
+	}
+
+	switch {
+	case credential.PasswordEnv != "":
+		password, ok := os.LookupEnv(credential.PasswordEnv)
+
+		if !ok {
+			return "", "", fmt.Errorf("credentials %q: environment variable %s is not set", camera.Credentials,
+				credential.PasswordEnv)
+		}
+
+		return username, password, nil
+	case credential.PasswordFile != "":
+		data, err := ioutil.ReadFile(credential.PasswordFile)
+
+		if err != nil {
+			return "", "", fmt.Errorf("credentials %q: %w", camera.Credentials, err)
+		}
+
+		return username, strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	return username, "", nil
+}
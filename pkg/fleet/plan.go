@@ -0,0 +1,306 @@
+package fleet
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/backup"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/network/rest"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"golang.org/x/net/context"
+	"strings"
+	"sync"
+)
+
+type fleetOptions struct {
+	concurrency int
+	networkOpts []rest.OptionRestHandler
+	cameras     []string
+	restoreOpts []backup.OptionRestore
+}
+
+type OptionFleet func(*fleetOptions)
+
+// Set how many cameras are planned or applied at the same time
+// Default: 4
+func FleetOptionConcurrency(concurrency int) OptionFleet {
+	return func(f *fleetOptions) {
+		f.concurrency = concurrency
+	}
+}
+
+// Set the network options used to reach every camera, e.g. a proxy
+func FleetOptionNetwork(networkOpts ...rest.OptionRestHandler) OptionFleet {
+	return func(f *fleetOptions) {
+		f.networkOpts = networkOpts
+	}
+}
+
+// Only plan the named cameras
+// Default: every camera of the fleet
+func FleetOptionCameras(names ...string) OptionFleet {
+	return func(f *fleetOptions) {
+		f.cameras = append(f.cameras, names...)
+	}
+}
+
+// Only plan the named sections of every camera, see backup.RestoreOptionSections
+// Default: every section
+func FleetOptionSections(names ...string) OptionFleet {
+	return func(f *fleetOptions) {
+		f.restoreOpts = append(f.restoreOpts, backup.RestoreOptionSections(names...))
+	}
+}
+
+func newFleetOptions(opts []OptionFleet) *fleetOptions {
+	options := &fleetOptions{
+		concurrency: 4,
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+
+	return options
+}
+
+// CameraPlan is what would change on a single camera, Err is set when the camera could not be planned
+type CameraPlan struct {
+	Camera string
+	Host   string
+	Diff   *backup.Diff
+	Err    error
+
+	config *Camera
+	client *reolinkapi.Camera
+}
+
+// Plan is what would change on every camera, in the order of the fleet
+type Plan struct {
+	Cameras []*CameraPlan
+}
+
+// Empty is true when every camera already matches the fleet
+func (p *Plan) Empty() bool {
+	for _, c := range p.Cameras {
+		if c.Err != nil || !c.Diff.Empty() || len(c.Diff.Failed) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Plan) String() string {
+	var b strings.Builder
+
+	changes, unchanged, failed := 0, 0, 0
+
+	for _, c := range p.Cameras {
+		fmt.Fprintf(&b, "# %s (%s)\n", c.Camera, c.Host)
+
+		switch {
+		case c.Err != nil:
+			failed++
+			fmt.Fprintf(&b, "! %v\n", c.Err)
+		case c.Diff.Empty() && len(c.Diff.Failed) == 0:
+			unchanged++
+			fmt.Fprintln(&b, "no changes")
+		default:
+			if !c.Diff.Empty() {
+				changes++
+			}
+
+			if len(c.Diff.Failed) > 0 {
+				failed++
+			}
+
+			b.WriteString(c.Diff.String())
+		}
+
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Plan: %d to change, %d unchanged, %d failed.\n", changes, unchanged, failed)
+
+	return b.String()
+}
+
+// Plan logs into every camera and compares it with the fleet, nothing is changed.
+// Cameras are planned concurrently, a camera that cannot be reached or compared is reported in its CameraPlan.
+// Cameras not started when ctx is done fail with the context's error.
+func (f *Fleet) Plan(ctx context.Context, opts ...OptionFleet) (*Plan, error) {
+	options := newFleetOptions(opts)
+
+	selected := map[string]bool{}
+
+	for _, name := range options.cameras {
+		selected[name] = true
+	}
+
+	var cameras []*Camera
+
+	for _, camera := range f.Cameras {
+		if len(selected) == 0 || selected[camera.Name] {
+			cameras = append(cameras, camera)
+			delete(selected, camera.Name)
+		}
+	}
+
+	for name := range selected {
+		return nil, fmt.Errorf("camera %q is not part of the fleet", name)
+	}
+
+	plan := &Plan{Cameras: make([]*CameraPlan, len(cameras))}
+
+	for i, camera := range cameras {
+		plan.Cameras[i] = &CameraPlan{Camera: camera.Name, Host: camera.Host, config: camera}
+	}
+
+	forEach(ctx, options.concurrency, len(plan.Cameras), func(i int) {
+		c := plan.Cameras[i]
+
+		if err := ctx.Err(); err != nil {
+			c.Err = err
+			return
+		}
+
+		c.client, c.Err = f.connect(c.config, options)
+
+		if c.Err != nil {
+			return
+		}
+
+		c.Diff, c.Err = backup.Compare(c.client, c.config.Desired, options.restoreOpts...)
+	})
+
+	return plan, nil
+}
+
+// CameraResult is the outcome of applying the plan to a single camera.
+// Err is set when the camera was not applied at all, sections that failed are in the Report.
+type CameraResult struct {
+	Camera string
+	Host   string
+	Report *backup.Report
+	Err    error
+}
+
+// Result is the outcome of Apply, in the order of the plan
+type Result struct {
+	Cameras []*CameraResult
+}
+
+// Err combines the cameras that failed into a single error, nil when every camera was applied
+func (r *Result) Err() error {
+	var failed []string
+
+	for _, c := range r.Cameras {
+		err := c.Err
+
+		if err == nil && c.Report != nil {
+			err = c.Report.Err()
+		}
+
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", c.Camera, err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d camera(s) failed: %s", len(failed), strings.Join(failed, "; "))
+}
+
+func (r *Result) String() string {
+	var b strings.Builder
+
+	for _, c := range r.Cameras {
+		fmt.Fprintf(&b, "# %s (%s)\n", c.Camera, c.Host)
+
+		if c.Err != nil {
+			fmt.Fprintf(&b, "! %v\n\n", c.Err)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s\n", c.Report)
+	}
+
+	return b.String()
+}
+
+// Apply makes the changes of the plan, the cameras are not compared again.
+// Cameras that failed to plan are reported as failed, cameras without changes are left alone.
+// Cameras not started when ctx is done fail with the context's error, a camera already being applied is finished.
+func (f *Fleet) Apply(ctx context.Context, plan *Plan, opts ...OptionFleet) *Result {
+	options := newFleetOptions(opts)
+
+	result := &Result{Cameras: make([]*CameraResult, len(plan.Cameras))}
+
+	forEach(ctx, options.concurrency, len(plan.Cameras), func(i int) {
+		c := plan.Cameras[i]
+		cameraResult := &CameraResult{Camera: c.Camera, Host: c.Host}
+		result.Cameras[i] = cameraResult
+
+		switch {
+		case c.Err != nil:
+			cameraResult.Err = fmt.Errorf("not planned: %w", c.Err)
+		case ctx.Err() != nil:
+			cameraResult.Err = ctx.Err()
+		default:
+			var restoreOpts []backup.OptionRestore
+
+			for name, password := range c.config.userPasswords {
+				restoreOpts = append(restoreOpts, backup.RestoreOptionUserPassword(name, password))
+			}
+
+			cameraResult.Report = backup.Apply(c.client, c.Diff, restoreOpts...)
+		}
+	})
+
+	return result
+}
+
+func (f *Fleet) connect(camera *Camera, options *fleetOptions) (*reolinkapi.Camera, error) {
+	username, password, err := f.login(camera)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reolinkapi.NewCamera(camera.Host,
+		reolinkapi.WithUsername(username),
+		reolinkapi.WithPassword(password),
+		reolinkapi.WithNetworkOptions(options.networkOpts...))
+}
+
+// run work for 0 to n-1 with at most concurrency at the same time
+func forEach(ctx context.Context, concurrency int, n int, work func(i int)) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			// work returns straight away with the context's error
+			work(i)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			work(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
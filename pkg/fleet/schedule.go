@@ -0,0 +1,147 @@
+package fleet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseSchedule turns schedule entries into the camera's 168 hour table (see models.Schedule).
+// An entry is "always", "never" or "<days> <from>-<to>", e.g. "mon-fri 08-18", "sat,sun 22:00-06:00" or
+// "daily 00-24". Days are sun to sat, ranges and lists may be combined. Hours are whole, the end is exclusive and a
+// range ending before it starts runs past midnight.
+func ParseSchedule(entries ...string) (string, error) {
+	table := make([]byte, 168)
+
+	for i := range table {
+		table[i] = '0'
+	}
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+
+		switch entry {
+		case "always":
+			for i := range table {
+				table[i] = '1'
+			}
+			continue
+		case "never":
+			continue
+		}
+
+		fields := strings.Fields(entry)
+
+		if len(fields) != 2 {
+			return "", fmt.Errorf("schedule entry %q is not \"<days> <from>-<to>\"", entry)
+		}
+
+		days, err := parseDays(fields[0])
+
+		if err != nil {
+			return "", fmt.Errorf("schedule entry %q: %w", entry, err)
+		}
+
+		from, to, err := parseHours(fields[1])
+
+		if err != nil {
+			return "", fmt.Errorf("schedule entry %q: %w", entry, err)
+		}
+
+		length := to - from
+
+		if length <= 0 {
+			length += 24
+		}
+
+		for _, day := range days {
+			for h := 0; h < length; h++ {
+				table[(day*24+from+h)%168] = '1'
+			}
+		}
+	}
+
+	return string(table), nil
+}
+
+func parseDays(spec string) ([]int, error) {
+	if spec == "daily" || spec == "*" {
+		return []int{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+
+	var days []int
+
+	for _, part := range strings.Split(spec, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+
+		first, err := weekday(bounds[0])
+
+		if err != nil {
+			return nil, err
+		}
+
+		last := first
+
+		if len(bounds) == 2 {
+			if last, err = weekday(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			days = append(days, day)
+
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+func weekday(name string) (int, error) {
+	for i, day := range weekdays {
+		if strings.HasPrefix(name, day) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown day %q", name)
+}
+
+func parseHours(spec string) (int, int, error) {
+	bounds := strings.SplitN(spec, "-", 2)
+
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("hours %q are not \"<from>-<to>\"", spec)
+	}
+
+	from, err := hour(bounds[0])
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := hour(bounds[1])
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return from, to % 24, nil
+}
+
+func hour(spec string) (int, error) {
+	spec = strings.TrimSuffix(spec, ":00")
+
+	h, err := strconv.Atoi(spec)
+
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("%q is not a whole hour between 00 and 24", spec)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,188 @@
+package imageprofile
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"golang.org/x/net/context"
+	"sort"
+	"sync"
+)
+
+// Profile is a named set of image and advanced image (ISP) options, e.g. "day" and "night".
+// Only the options given are changed when the profile is applied, every other setting is kept.
+type Profile struct {
+	Name     string
+	Image    []api.OptionImageSetting
+	Advanced []api.OptionAdvancedImageSetting
+}
+
+// Profiles stores the named profiles of a single camera and applies them.
+type Profiles struct {
+	camera   *reolinkapi.Camera
+	mu       sync.Mutex
+	profiles map[string]*Profile
+	active   string
+}
+
+// Create a new profile store for the camera
+func NewProfiles(camera *reolinkapi.Camera, profiles ...*Profile) *Profiles {
+	p := &Profiles{
+		camera:   camera,
+		profiles: make(map[string]*Profile, len(profiles)),
+	}
+
+	for _, profile := range profiles {
+		p.Add(profile)
+	}
+
+	return p
+}
+
+// Add a profile, replacing any profile with the same name
+func (p *Profiles) Add(profile *Profile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.profiles[profile.Name] = profile
+}
+
+// Remove the profile with the given name
+func (p *Profiles) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.profiles, name)
+}
+
+// Get the profile with the given name
+func (p *Profiles) Get(name string) (*Profile, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, ok := p.profiles[name]
+
+	return profile, ok
+}
+
+// Names of all the stored profiles in alphabetical order
+func (p *Profiles) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.profiles))
+
+	for name := range p.profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Active returns the name of the last profile applied successfully, empty if none has been applied yet
+func (p *Profiles) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.active
+}
+
+// Apply the named profile to the camera.
+// Both the image and advanced image settings are validated against the camera's ranges before anything is sent.
+// If the second setting is rejected by the camera the first one is rolled back, so the camera either ends up with
+// the whole profile or with its previous settings.
+// ctx is checked before every request, a request already sent is finished. When ctx is done after the advanced image
+// settings were sent they are rolled back like a rejected image setting.
+func (p *Profiles) Apply(ctx context.Context, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, ok := p.profiles[name]
+
+	if !ok {
+		return fmt.Errorf("image profile %q does not exist", name)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("image profile %q: %w", name, err)
+	}
+
+	handler := p.camera.RestHandler
+
+	imageSettings, err := p.camera.GetImageSettings()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("image profile %q: %w", name, err)
+	}
+
+	ispSettings, err := p.camera.GetAdvanceImageSettings()(handler)
+
+	if err != nil {
+		return err
+	}
+
+	// dry run the options on copies first so an invalid profile never touches the camera
+	image := *imageSettings.Image
+	for _, op := range profile.Image {
+		op(&image)
+	}
+
+	if imageSettings.Range != nil {
+		if err := imageSettings.Range.Validate(&image); err != nil {
+			return fmt.Errorf("image profile %q: %w", name, err)
+		}
+	}
+
+	isp := *ispSettings.Isp
+	for _, op := range profile.Advanced {
+		op(&isp)
+	}
+
+	if ispSettings.Range != nil {
+		if err := ispSettings.Range.Validate(&isp); err != nil {
+			return fmt.Errorf("image profile %q: %w", name, err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("image profile %q: %w", name, err)
+	}
+
+	if len(profile.Advanced) > 0 {
+		_, err = p.camera.SetAdvanceImageSettings(api.ImageAdvancedOptionBase(&isp))(handler)
+
+		if err != nil {
+			return fmt.Errorf("image profile %q: %w", name, err)
+		}
+	}
+
+	if len(profile.Image) > 0 {
+		if err = ctx.Err(); err == nil {
+			_, err = p.camera.SetImageSettings(api.ImageOptionBase(&image))(handler)
+		}
+
+		if err != nil {
+			if len(profile.Advanced) > 0 {
+				_, rollbackErr := p.camera.SetAdvanceImageSettings(
+					api.ImageAdvancedOptionBase(ispSettings.Isp))(handler)
+
+				if rollbackErr != nil {
+					return fmt.Errorf("image profile %q: %v, rolling back advanced image settings failed: %v",
+						name, err, rollbackErr)
+				}
+			}
+
+			return fmt.Errorf("image profile %q: %w", name, err)
+		}
+	}
+
+	p.active = name
+
+	return nil
+}
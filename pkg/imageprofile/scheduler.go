@@ -0,0 +1,277 @@
+package imageprofile
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"time"
+)
+
+type Trigger uint
+
+const (
+	TRIGGER_CLOCK Trigger = iota
+	TRIGGER_SUNRISE
+	TRIGGER_SUNSET
+)
+
+func (t Trigger) String() string {
+	return []string{"clock", "sunrise", "sunset"}[t]
+}
+
+// Switch applies Profile every day at the trigger.
+// For TRIGGER_CLOCK, At is the wall clock time of day. For TRIGGER_SUNRISE and TRIGGER_SUNSET, At is an offset
+// from the event, e.g. -30 minutes to switch half an hour before sunset.
+type Switch struct {
+	Profile string
+	Trigger Trigger
+	At      time.Duration
+}
+
+type schedulerOptions struct {
+	latitude    float64
+	longitude   float64
+	hasPosition bool
+	location    *time.Location
+	switches    []Switch
+	now         func() time.Time
+}
+
+type OptionScheduler func(*schedulerOptions)
+
+// Set the camera's position, required for sunrise and sunset switches
+func SchedulerOptionPosition(latitude float64, longitude float64) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.latitude = latitude
+		s.longitude = longitude
+		s.hasPosition = true
+	}
+}
+
+// Set the time zone the clock switches are expressed in
+// Default: time.Local
+func SchedulerOptionLocation(location *time.Location) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.location = location
+	}
+}
+
+// Switch to the profile every day at hour:minute
+func SchedulerOptionAt(hour int, minute int, profile string) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.switches = append(s.switches, Switch{
+			Profile: profile,
+			Trigger: TRIGGER_CLOCK,
+			At:      time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute,
+		})
+	}
+}
+
+// Switch to the profile every day at sunrise shifted by offset
+func SchedulerOptionSunrise(offset time.Duration, profile string) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.switches = append(s.switches, Switch{
+			Profile: profile,
+			Trigger: TRIGGER_SUNRISE,
+			At:      offset,
+		})
+	}
+}
+
+// Switch to the profile every day at sunset shifted by offset
+func SchedulerOptionSunset(offset time.Duration, profile string) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.switches = append(s.switches, Switch{
+			Profile: profile,
+			Trigger: TRIGGER_SUNSET,
+			At:      offset,
+		})
+	}
+}
+
+// Replace the clock used by the scheduler, mostly useful for testing
+// Default: time.Now
+func SchedulerOptionClock(now func() time.Time) OptionScheduler {
+	return func(s *schedulerOptions) {
+		s.now = now
+	}
+}
+
+// Scheduler switches between the profiles of a Profiles store at fixed clock times or at sunrise and sunset.
+type Scheduler struct {
+	*schedulerOptions
+	profiles *Profiles
+}
+
+// Create a new scheduler for the profiles.
+// Every switch must reference a stored profile and sunrise/sunset switches need SchedulerOptionPosition.
+func NewScheduler(profiles *Profiles, opts ...OptionScheduler) (*Scheduler, error) {
+	options := &schedulerOptions{
+		location: time.Local,
+		now:      time.Now,
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	if len(options.switches) == 0 {
+		return nil, fmt.Errorf("scheduler needs at least one switch")
+	}
+
+	for _, s := range options.switches {
+		if _, ok := profiles.Get(s.Profile); !ok {
+			return nil, fmt.Errorf("scheduler switch at %s references unknown image profile %q", s.Trigger, s.Profile)
+		}
+
+		if s.Trigger != TRIGGER_CLOCK && !options.hasPosition {
+			return nil, fmt.Errorf("scheduler switch at %s needs the camera position", s.Trigger)
+		}
+	}
+
+	return &Scheduler{
+		schedulerOptions: options,
+		profiles:         profiles,
+	}, nil
+}
+
+type switchEvent struct {
+	at      time.Time
+	profile string
+}
+
+// the switch events of the calendar day of date, in no particular order.
+// sunrise/sunset switches are skipped on days the sun does not rise or set.
+func (s *Scheduler) eventsOn(date time.Time) []switchEvent {
+	y, m, d := date.In(s.location).Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, s.location)
+
+	var events []switchEvent
+
+	var sunrise, sunset time.Time
+	var sunErr error
+
+	if s.hasPosition {
+		sunrise, sunset, sunErr = SunriseSunset(midnight, s.latitude, s.longitude)
+	}
+
+	for _, sw := range s.switches {
+		switch sw.Trigger {
+		case TRIGGER_CLOCK:
+			// built from the wall clock, adding At to midnight is an hour off on daylight saving days
+			at := time.Date(y, m, d, int(sw.At/time.Hour), int(sw.At%time.Hour/time.Minute),
+				int(sw.At%time.Minute/time.Second), 0, s.location)
+			events = append(events, switchEvent{at, sw.Profile})
+		case TRIGGER_SUNRISE:
+			if sunErr == nil {
+				events = append(events, switchEvent{sunrise.Add(sw.At), sw.Profile})
+			}
+		case TRIGGER_SUNSET:
+			if sunErr == nil {
+				events = append(events, switchEvent{sunset.Add(sw.At), sw.Profile})
+			}
+		}
+	}
+
+	return events
+}
+
+// Current returns the profile that should be active at the given time, i.e. the profile of the last switch before it
+func (s *Scheduler) Current(at time.Time) (string, error) {
+	var last *switchEvent
+
+	// look back up to a week in case the sun based switches are skipped around the poles
+	for day := 0; day <= 7 && last == nil; day++ {
+		for _, e := range s.eventsOn(at.AddDate(0, 0, -day)) {
+			e := e
+			if e.at.After(at) {
+				continue
+			}
+
+			if last == nil || e.at.After(last.at) {
+				last = &e
+			}
+		}
+	}
+
+	if last == nil {
+		return "", fmt.Errorf("no image profile switch happened in the week before %s", at)
+	}
+
+	return last.profile, nil
+}
+
+// Next returns the time and profile of the first switch after the given time
+func (s *Scheduler) Next(after time.Time) (time.Time, string, error) {
+	var next *switchEvent
+
+	for day := 0; day <= 7 && next == nil; day++ {
+		for _, e := range s.eventsOn(after.AddDate(0, 0, day)) {
+			e := e
+			if !e.at.After(after) {
+				continue
+			}
+
+			if next == nil || e.at.Before(next.at) {
+				next = &e
+			}
+		}
+	}
+
+	if next == nil {
+		return time.Time{}, "", fmt.Errorf("no image profile switch happens in the week after %s", after)
+	}
+
+	return next.at, next.profile, nil
+}
+
+// Run applies the current profile straight away and then switches profiles as scheduled until ctx is cancelled.
+// Errors applying a profile are sent on the returned channel, the scheduler keeps running and tries again at the
+// next switch. Errors are dropped while an earlier one is still unread.
+// The channel is closed when the scheduler stops.
+func (s *Scheduler) Run(ctx context.Context) chan error {
+	errChan := make(chan error, 1)
+
+	report := func(err error) {
+		select {
+		case errChan <- err:
+		default:
+			// the caller is not reading errors, drop it rather than stall the scheduler
+		}
+	}
+
+	go func() {
+		defer close(errChan)
+
+		profile, err := s.Current(s.now())
+
+		if err != nil {
+			report(err)
+		} else if err := s.profiles.Apply(ctx, profile); err != nil {
+			report(err)
+		}
+
+		for {
+			at, profile, err := s.Next(s.now())
+
+			if err != nil {
+				report(err)
+				return
+			}
+
+			timer := time.NewTimer(at.Sub(s.now()))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return // terminate goroutine
+			case <-timer.C:
+			}
+
+			if err := s.profiles.Apply(ctx, profile); err != nil {
+				report(err)
+			}
+		}
+	}()
+
+	return errChan
+}
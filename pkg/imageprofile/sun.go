@@ -0,0 +1,67 @@
+package imageprofile
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	julianUnixEpoch = 2440587.5
+	julian2000      = 2451545.0
+	degrees         = math.Pi / 180
+)
+
+func toJulian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + julianUnixEpoch
+}
+
+func fromJulian(j float64) time.Time {
+	return time.Unix(int64(math.Round((j-julianUnixEpoch)*86400)), 0).UTC()
+}
+
+// SunriseSunset computes the sunrise and sunset for the calendar day of date (in date's location) at the given
+// latitude and longitude in degrees, north and east being positive.
+// The result is accurate to within a couple of minutes, which is plenty for switching image profiles.
+// An error is returned when the sun does not rise or set on that day (polar day or night).
+// See https://en.wikipedia.org/wiki/Sunrise_equation
+func SunriseSunset(date time.Time, latitude float64, longitude float64) (time.Time, time.Time, error) {
+	// days since J2000 of the calendar day, the longitude then shifts it to the local solar noon
+	y, m, d := date.Date()
+	n := math.Round(toJulian(time.Date(y, m, d, 12, 0, 0, 0, time.UTC)) - julian2000)
+
+	meanSolarTime := n + 0.0008 - longitude/360
+
+	meanAnomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	center := 1.9148*math.Sin(meanAnomaly*degrees) +
+		0.02*math.Sin(2*meanAnomaly*degrees) +
+		0.0003*math.Sin(3*meanAnomaly*degrees)
+	eclipticLongitude := math.Mod(meanAnomaly+center+180+102.9372, 360)
+
+	transit := julian2000 + meanSolarTime +
+		0.0053*math.Sin(meanAnomaly*degrees) -
+		0.0069*math.Sin(2*eclipticLongitude*degrees)
+
+	sinDeclination := math.Sin(eclipticLongitude*degrees) * math.Sin(23.4397*degrees)
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+
+	cosHourAngle := (math.Sin(-0.833*degrees) - math.Sin(latitude*degrees)*sinDeclination) /
+		(math.Cos(latitude*degrees) * cosDeclination)
+
+	if cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("the sun does not rise on %s at %f, %f",
+			date.Format("2006-01-02"), latitude, longitude)
+	}
+
+	if cosHourAngle < -1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("the sun does not set on %s at %f, %f",
+			date.Format("2006-01-02"), latitude, longitude)
+	}
+
+	hourAngle := math.Acos(cosHourAngle) / degrees
+
+	sunrise := fromJulian(transit - hourAngle/360).In(date.Location())
+	sunset := fromJulian(transit + hourAngle/360).In(date.Location())
+
+	return sunrise, sunset, nil
+}
@@ -0,0 +1,319 @@
+package joystick
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"golang.org/x/net/context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Vector is a joystick position, every axis ranges from -1 to 1.
+// Pan is positive to the right, Tilt is positive up and Zoom is positive to zoom in.
+type Vector struct {
+	Pan  float64
+	Tilt float64
+	Zoom float64
+}
+
+// Command is a single PtzCtrl operation with its speed, the speed is 0 for Stop
+type Command struct {
+	Operation string
+	Speed     int
+}
+
+// STOP is the command sent while the joystick is centred
+var STOP = Command{Operation: "Stop"}
+
+func (c Command) String() string {
+	if c.Operation == STOP.Operation {
+		return c.Operation
+	}
+
+	return fmt.Sprintf("%s@%d", c.Operation, c.Speed)
+}
+
+// the eight directions clockwise from the right, one per 45 degree sector
+var directions = []string{"Right", "RightDown", "Down", "LeftDown", "Left", "LeftUp", "Up", "RightUp"}
+
+type joystickOptions struct {
+	deadZone    float64
+	minSpeed    int
+	maxSpeed    int
+	zoomSpeed   int
+	speedSteps  int
+	minInterval time.Duration
+	idleTimeout time.Duration
+}
+
+type OptionJoystick func(*joystickOptions)
+
+// Ignore deflections below the dead zone, so a centred stick that is not quite at 0 stops the camera
+// Default: 0.15
+func JoystickOptionDeadZone(deadZone float64) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.deadZone = deadZone
+	}
+}
+
+// Set the pan/tilt speed at the smallest and the full deflection
+// Default: 1 and 64
+func JoystickOptionSpeedRange(min int, max int) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.minSpeed = min
+		j.maxSpeed = max
+	}
+}
+
+// Set the zoom speed, zooming does not scale with the deflection
+// Default: 60
+func JoystickOptionZoomSpeed(speed int) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.zoomSpeed = speed
+	}
+}
+
+// Set the number of distinct pan/tilt speeds, fewer steps means fewer commands while the stick wobbles
+// Default: 8
+func JoystickOptionSpeedSteps(steps int) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.speedSteps = steps
+	}
+}
+
+// Set the minimum time between two commands sent to the camera
+// Default: 200 milliseconds
+func JoystickOptionMinInterval(interval time.Duration) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.minInterval = interval
+	}
+}
+
+// Stop the camera when no vector was received for the timeout, in case the operator UI goes away mid-move.
+// Zero disables the timeout.
+// Default: 1 second
+func JoystickOptionIdleTimeout(timeout time.Duration) OptionJoystick {
+	return func(j *joystickOptions) {
+		j.idleTimeout = timeout
+	}
+}
+
+// Joystick drives the camera's PTZ from continuous joystick vectors.
+// Vectors are quantised to the nearest PtzCtrl operation and speed, vectors mapping onto the command last sent are
+// dropped and the remaining commands are rate limited, only the latest one is sent when several arrive in between.
+type Joystick struct {
+	*joystickOptions
+	camera *reolinkapi.Camera
+
+	mu       sync.Mutex
+	latest   Command
+	received time.Time
+	update   chan struct{}
+}
+
+// Create a new joystick for the camera, call Run to start sending commands
+func NewJoystick(camera *reolinkapi.Camera, opts ...OptionJoystick) *Joystick {
+	options := &joystickOptions{
+		deadZone:    0.15,
+		minSpeed:    1,
+		maxSpeed:    64,
+		zoomSpeed:   60,
+		speedSteps:  8,
+		minInterval: 200 * time.Millisecond,
+		idleTimeout: time.Second,
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	return &Joystick{
+		joystickOptions: options,
+		camera:          camera,
+		latest:          STOP,
+		update:          make(chan struct{}, 1),
+	}
+}
+
+// Quantise maps a vector onto the nearest command.
+// Pan/tilt wins over zoom when both are deflected, as the camera runs a single operation at a time.
+func (j *Joystick) Quantise(v Vector) Command {
+	pan := clamp(v.Pan)
+	tilt := clamp(v.Tilt)
+	zoom := clamp(v.Zoom)
+
+	magnitude := math.Min(math.Hypot(pan, tilt), 1)
+
+	if magnitude >= j.deadZone && magnitude >= math.Abs(zoom) {
+		// screen angle clockwise from the right, tilt up is negative on screen
+		angle := math.Atan2(-tilt, pan) / (math.Pi / 4)
+		sector := int(math.Round(angle)+8) % 8
+
+		// scale the deflection beyond the dead zone onto the speed steps
+		scaled := (magnitude - j.deadZone) / (1 - j.deadZone)
+		step := math.Ceil(scaled * float64(j.speedSteps))
+
+		if step < 1 {
+			step = 1
+		}
+
+		speed := j.minSpeed
+		if j.speedSteps > 1 {
+			speed += int(math.Round((step - 1) / float64(j.speedSteps-1) * float64(j.maxSpeed-j.minSpeed)))
+		}
+
+		return Command{Operation: directions[sector], Speed: speed}
+	}
+
+	if math.Abs(zoom) >= j.deadZone {
+		if zoom > 0 {
+			return Command{Operation: "ZoomInc", Speed: j.zoomSpeed}
+		}
+
+		return Command{Operation: "ZoomDec", Speed: j.zoomSpeed}
+	}
+
+	return STOP
+}
+
+func clamp(f float64) float64 {
+	if math.IsNaN(f) {
+		return 0
+	}
+
+	return math.Max(-1, math.Min(1, f))
+}
+
+// Set the joystick position, it is picked up by Run. Set never blocks.
+func (j *Joystick) Set(v Vector) {
+	command := j.Quantise(v)
+
+	j.mu.Lock()
+	j.latest = command
+	j.received = time.Now()
+	j.mu.Unlock()
+
+	select {
+	case j.update <- struct{}{}:
+	default:
+	}
+}
+
+// send the command through the camera's ptz and zoom mixins
+func (j *Joystick) send(command Command) error {
+	handler := j.camera.RestHandler
+	speed := api.PtzOptionOpsSpeed(command.Speed)
+
+	var err error
+
+	switch command.Operation {
+	case "Left":
+		_, err = j.camera.MoveLeft(speed)(handler)
+	case "Right":
+		_, err = j.camera.MoveRight(speed)(handler)
+	case "Up":
+		_, err = j.camera.MoveUp(speed)(handler)
+	case "Down":
+		_, err = j.camera.MoveDown(speed)(handler)
+	case "LeftUp":
+		_, err = j.camera.MoveLeftUp(speed)(handler)
+	case "LeftDown":
+		_, err = j.camera.MoveLeftDown(speed)(handler)
+	case "RightUp":
+		_, err = j.camera.MoveRightUp(speed)(handler)
+	case "RightDown":
+		_, err = j.camera.MoveRightDown(speed)(handler)
+	case "ZoomInc":
+		_, err = j.camera.StartZoomingIn(api.ZoomOptionSpeed(command.Speed))(handler)
+	case "ZoomDec":
+		_, err = j.camera.StartZoomingOut(api.ZoomOptionSpeed(command.Speed))(handler)
+	default:
+		_, err = j.camera.StopPtz()(handler)
+	}
+
+	return err
+}
+
+// Run sends the joystick commands to the camera until ctx is cancelled, the camera is stopped on the way out.
+// Errors are sent on the returned channel, a failed command is retried with the next vector.
+// The channel is closed when the joystick stops.
+func (j *Joystick) Run(ctx context.Context) chan error {
+	errChan := make(chan error, 1)
+
+	report := func(err error) {
+		select {
+		case errChan <- err:
+		default:
+			// the caller is not reading errors, drop it rather than stall the joystick
+		}
+	}
+
+	go func() {
+		defer close(errChan)
+
+		sent := STOP
+		var lastSend time.Time
+
+		idle := time.NewTimer(time.Hour)
+		idle.Stop()
+		defer idle.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if sent != STOP {
+					if err := j.send(STOP); err != nil {
+						report(err)
+					}
+				}
+				return // terminate goroutine
+			case <-idle.C:
+				j.mu.Lock()
+				if time.Since(j.received) >= j.idleTimeout {
+					j.latest = STOP
+				}
+				j.mu.Unlock()
+			case <-j.update:
+			}
+
+			// rate limit, the latest command after the wait is the one sent
+			if wait := j.minInterval - time.Since(lastSend); wait > 0 {
+				timer := time.NewTimer(wait)
+
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					continue
+				case <-timer.C:
+				}
+			}
+
+			j.mu.Lock()
+			command := j.latest
+			j.mu.Unlock()
+
+			if j.idleTimeout > 0 && command != STOP {
+				idle.Reset(j.idleTimeout)
+			}
+
+			if command == sent {
+				continue
+			}
+
+			lastSend = time.Now()
+
+			if err := j.send(command); err != nil {
+				report(err)
+				// unknown camera state, let the next vector send again
+				sent = Command{}
+				continue
+			}
+
+			sent = command
+		}
+	}()
+
+	return errChan
+}
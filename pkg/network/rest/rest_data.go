@@ -15,3 +15,14 @@ type Error struct {
 	Detail  string `json:"detail"`
 	RspCode int    `json:"rspCode"`
 }
+
+// CameraError is returned by Request when the camera answers a command with a non zero code
+type CameraError struct {
+	Cmd     string
+	Detail  string
+	RspCode int
+}
+
+func (e *CameraError) Error() string {
+	return e.Detail
+}
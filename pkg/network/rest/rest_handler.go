@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/net/context"
 	"golang.org/x/net/proxy"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"time"
@@ -194,68 +196,155 @@ func NewRestHandler(host string, opts ...OptionRestHandler) *RestHandler {
 // payload: the json data
 // auth: alters the request to include auth token on true
 func (rh *RestHandler) Request(method string, payload interface{}, command string) (*GeneralData, error) {
+	return rh.RequestContext(context.Background(), method, payload, command)
+}
+
+// RequestContext does the http request like Request, the request is aborted when ctx is done
+func (rh *RestHandler) RequestContext(ctx context.Context, method string, payload interface{}, command string) (
+	*GeneralData, error) {
 
 	params := url.Values{}
 	params.Add("cmd", command)
 
-	respBody, err := rh.RequestRaw(method, payload, params)
+	respBody, err := rh.requestRaw(ctx, method, payload, params)
 
 	if err != nil {
 		return nil, err
 	}
 
+	return rh.decodeResult(respBody)
+}
+
+// decode the camera's answer to a single command
+func (rh *RestHandler) decodeResult(respBody []byte) (*GeneralData, error) {
 	var results []*GeneralData
 
-	err = json.Unmarshal(respBody, &results)
+	err := json.Unmarshal(respBody, &results)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if len(results) == 0 {
+		return nil, fmt.Errorf("camera sent an empty response")
+	}
+
 	result := results[0]
 	if result.Code != 0 {
-		return nil, errors.New(result.Error.Detail)
+		return nil, &CameraError{
+			Cmd:     result.Cmd,
+			Detail:  result.Error.Detail,
+			RspCode: result.Error.RspCode,
+		}
 	}
 
 	return result, nil
 }
 
 func (rh *RestHandler) RequestRaw(method string, payload interface{}, params url.Values) ([]byte, error) {
-	var urlConcat string
-	if rh.port > 0 {
-		urlConcat = fmt.Sprintf("%s:%d/%s", rh.host, rh.port, rh.endpoint)
-	} else {
-		urlConcat = fmt.Sprintf("%s/%s", rh.host, rh.endpoint)
+	return rh.requestRaw(context.Background(), method, payload, params)
+}
+
+func (rh *RestHandler) requestRaw(ctx context.Context, method string, payload interface{}, params url.Values) (
+	[]byte, error) {
+	var data []byte
+
+	data, err := json.Marshal([]interface{}{payload})
+
+	if err != nil {
+		return nil, err
 	}
 
-	urlConcat = fmt.Sprintf("%s://%s", rh.scheme.String(), urlConcat)
+	reqUrl, err := rh.requestUrl(params)
 
-	params.Add("token", rh.token)
+	if err != nil {
+		return nil, err
+	}
 
-	urlConcat = fmt.Sprintf("%s?%s", urlConcat, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl.String(), bytes.NewBuffer(data))
 
-	var data []byte
+	if err != nil {
+		return nil, err
+	}
 
-	data, err := json.Marshal([]interface{}{payload})
+	req.Header = headers
+
+	return rh.do(req)
+}
+
+// RequestMultipart uploads the content read from file as a multipart form, e.g. a firmware image.
+// The upload is aborted when ctx is done.
+func (rh *RestHandler) RequestMultipart(ctx context.Context, command string, fieldName string, fileName string,
+	file io.Reader) (*GeneralData, error) {
+	params := url.Values{}
+	params.Add("cmd", command)
+
+	reqUrl, err := rh.requestUrl(params)
 
 	if err != nil {
 		return nil, err
 	}
 
-	reqUrl, err := url.Parse(urlConcat)
+	// stream the file instead of holding a whole firmware image in memory
+	bodyReader, bodyWriter := io.Pipe()
+	form := multipart.NewWriter(bodyWriter)
+
+	go func() {
+		part, err := form.CreateFormFile(fieldName, fileName)
+
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+
+		if err == nil {
+			err = form.Close()
+		}
+
+		_ = bodyWriter.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqUrl.String(), bodyReader)
 
 	if err != nil {
+		_ = bodyReader.Close()
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, reqUrl.String(), bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+
+	respBody, err := rh.do(req)
+
+	// unblock the writer if the request ended before reading the whole body
+	_ = bodyReader.Close()
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header = headers
+	return rh.decodeResult(respBody)
+}
+
+// build the url of a request, adding the token to params
+func (rh *RestHandler) requestUrl(params url.Values) (*url.URL, error) {
+	var urlConcat string
+	if rh.port > 0 {
+		urlConcat = fmt.Sprintf("%s:%d/%s", rh.host, rh.port, rh.endpoint)
+	} else {
+		urlConcat = fmt.Sprintf("%s/%s", rh.host, rh.endpoint)
+	}
 
+	urlConcat = fmt.Sprintf("%s://%s", rh.scheme.String(), urlConcat)
+
+	params.Add("token", rh.token)
+
+	urlConcat = fmt.Sprintf("%s?%s", urlConcat, params.Encode())
+
+	return url.Parse(urlConcat)
+}
+
+// send the request through the configured client or proxy and read the response body
+func (rh *RestHandler) do(req *http.Request) ([]byte, error) {
 	var client *http.Client
 
 	if rh.client != nil {
@@ -349,6 +438,8 @@ func (rh *RestHandler) RequestRaw(method string, payload interface{}, params url
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
 	body, err := ioutil.ReadAll(resp.Body)
 
 	if err != nil {
@@ -358,6 +449,16 @@ func (rh *RestHandler) RequestRaw(method string, payload interface{}, params url
 	return body, nil
 }
 
+// Point the handler at a different host, e.g. after the camera's address changed
+func (rh *RestHandler) SetHost(host string) {
+	rh.host = host
+}
+
+// Get the host the handler sends its requests to
+func (rh *RestHandler) GetHost() string {
+	return rh.host
+}
+
 // Set the current token
 func (rh *RestHandler) SetToken(token string) {
 	rh.token = token
@@ -7,6 +7,18 @@ import (
 
 type NetworkPortOption func(ports *models.NetworkPort)
 
+// WithNetworkPortOptionBase Start from a complete set of ports instead of the camera's current ones, options
+// passed after it are applied on top
+func WithNetworkPortOptionBase(base *models.NetworkPort) NetworkPortOption {
+	return func(ports *models.NetworkPort) {
+		if base == nil {
+			return
+		}
+
+		*ports = *base
+	}
+}
+
 // WithNetworkPortOptionHttpEnable An option for SetNetworkPort to set the httpEnable
 func WithNetworkPortOptionHttpEnable(enable enum.Toggle) NetworkPortOption {
 	return func(nm *models.NetworkPort) {
@@ -89,3 +101,299 @@ func WithNetworkPortOptionRtspPort(rtsp int) NetworkPortOption {
 		nm.RtspPort = rtsp
 	}
 }
+
+type NtpOption func(ntp *models.NetworkNTP)
+
+// WithNtpOptionBase An option for SetNtp to start from a complete configuration instead of the camera's current one
+func WithNtpOptionBase(base *models.NetworkNTP) NtpOption {
+	return func(ntp *models.NetworkNTP) {
+		if base == nil {
+			return
+		}
+
+		*ntp = *base
+	}
+}
+
+// WithNtpOptionEnable An option for SetNtp to enable or disable time synchronisation
+func WithNtpOptionEnable(enable enum.Toggle) NtpOption {
+	return func(ntp *models.NetworkNTP) {
+		ntp.Enable = enable
+	}
+}
+
+// WithNtpOptionServer An option for SetNtp to set the NTP server and port
+// Default value of port is 123
+func WithNtpOptionServer(server string, port int) NtpOption {
+	return func(ntp *models.NetworkNTP) {
+		ntp.Server = server
+		ntp.Port = port
+	}
+}
+
+// WithNtpOptionInterval An option for SetNtp to set the synchronisation interval in minutes
+func WithNtpOptionInterval(minutes int) NtpOption {
+	return func(ntp *models.NetworkNTP) {
+		ntp.Interval = minutes
+	}
+}
+
+type DdnsOption func(ddns *models.NetworkDDNS)
+
+// WithDdnsOptionBase An option for SetDdns to start from a complete configuration instead of the camera's current one
+func WithDdnsOptionBase(base *models.NetworkDDNS) DdnsOption {
+	return func(ddns *models.NetworkDDNS) {
+		if base == nil {
+			return
+		}
+
+		*ddns = *base
+	}
+}
+
+// WithDdnsOptionEnable An option for SetDdns to enable or disable DDNS
+func WithDdnsOptionEnable(enable enum.Toggle) DdnsOption {
+	return func(ddns *models.NetworkDDNS) {
+		ddns.Enable = enable
+	}
+}
+
+// WithDdnsOptionType An option for SetDdns to set the DDNS provider, e.g. no-ip, 3322 or dyndns
+func WithDdnsOptionType(ddnsType string) DdnsOption {
+	return func(ddns *models.NetworkDDNS) {
+		ddns.Type = ddnsType
+	}
+}
+
+// WithDdnsOptionDomain An option for SetDdns to set the domain to update
+func WithDdnsOptionDomain(domain string) DdnsOption {
+	return func(ddns *models.NetworkDDNS) {
+		ddns.Domain = domain
+	}
+}
+
+// WithDdnsOptionCredentials An option for SetDdns to set the DDNS account
+func WithDdnsOptionCredentials(username string, password string) DdnsOption {
+	return func(ddns *models.NetworkDDNS) {
+		ddns.Username = username
+		ddns.Password = password
+	}
+}
+
+type EmailOption func(email *models.NetworkEmail)
+
+// WithEmailOptionBase An option for SetEmail and TestEmail to start from a complete configuration instead of the
+// camera's current one, options passed after it are applied on top
+func WithEmailOptionBase(base *models.NetworkEmail) EmailOption {
+	return func(email *models.NetworkEmail) {
+		if base == nil {
+			return
+		}
+
+		*email = *base
+	}
+}
+
+// WithEmailOptionServer An option for SetEmail to set the SMTP server and port
+func WithEmailOptionServer(server string, port int) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.SmtpServer = server
+		email.SmtpPort = port
+	}
+}
+
+// WithEmailOptionSSL An option for SetEmail to enable or disable SSL/TLS
+func WithEmailOptionSSL(ssl enum.Toggle) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.SSL = ssl
+	}
+}
+
+// WithEmailOptionCredentials An option for SetEmail to set the SMTP account
+func WithEmailOptionCredentials(username string, password string) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.Username = username
+		email.Password = password
+	}
+}
+
+// WithEmailOptionNickname An option for SetEmail to set the sender name
+func WithEmailOptionNickname(nickname string) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.Nickname = nickname
+	}
+}
+
+// WithEmailOptionRecipients An option for SetEmail to set up to three recipients, missing recipients are cleared
+func WithEmailOptionRecipients(addresses ...string) EmailOption {
+	return func(email *models.NetworkEmail) {
+		addrs := make([]string, 3)
+		copy(addrs, addresses)
+
+		email.Addr1 = addrs[0]
+		email.Addr2 = addrs[1]
+		email.Addr3 = addrs[2]
+	}
+}
+
+// WithEmailOptionAttachment An option for SetEmail to set what is attached, e.g. picture, video or none
+func WithEmailOptionAttachment(attachment string) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.Attachment = attachment
+	}
+}
+
+// WithEmailOptionInterval An option for SetEmail to set the minimum time between emails, e.g. "5 Minutes"
+func WithEmailOptionInterval(interval string) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.Interval = interval
+	}
+}
+
+// WithEmailOptionSchedule An option for SetEmail to set when emails are sent
+func WithEmailOptionSchedule(schedule models.Schedule) EmailOption {
+	return func(email *models.NetworkEmail) {
+		email.Schedule = schedule
+	}
+}
+
+type FtpOption func(ftp *models.NetworkFTP)
+
+// WithFtpOptionBase An option for SetFtp and TestFtp to start from a complete configuration instead of the
+// camera's current one, options passed after it are applied on top
+func WithFtpOptionBase(base *models.NetworkFTP) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		if base == nil {
+			return
+		}
+
+		*ftp = *base
+	}
+}
+
+// WithFtpOptionServer An option for SetFtp to set the FTP server and port
+// Default value of port is 21
+func WithFtpOptionServer(server string, port int) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Server = server
+		ftp.Port = port
+	}
+}
+
+// WithFtpOptionCredentials An option for SetFtp to set the FTP account, this also turns anonymous login off
+func WithFtpOptionCredentials(username string, password string) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Username = username
+		ftp.Password = password
+		ftp.Anonymous = enum.Disabled
+	}
+}
+
+// WithFtpOptionAnonymous An option for SetFtp to log in anonymously
+func WithFtpOptionAnonymous(anonymous enum.Toggle) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Anonymous = anonymous
+	}
+}
+
+// WithFtpOptionRemoteDir An option for SetFtp to set the upload directory
+func WithFtpOptionRemoteDir(remoteDir string) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.RemoteDir = remoteDir
+	}
+}
+
+// WithFtpOptionInterval An option for SetFtp to set the upload interval in seconds
+func WithFtpOptionInterval(seconds int) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Interval = seconds
+	}
+}
+
+// WithFtpOptionMaxSize An option for SetFtp to set the maximum size of an upload in MB
+func WithFtpOptionMaxSize(megabytes int) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.MaxSize = megabytes
+	}
+}
+
+// WithFtpOptionMode An option for SetFtp to set the transfer mode, 0 auto, 1 passive or 2 active
+func WithFtpOptionMode(mode int) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Mode = mode
+	}
+}
+
+// WithFtpOptionStreamType An option for SetFtp to set the uploaded stream, 0 main stream or 1 sub stream
+func WithFtpOptionStreamType(streamType int) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.StreamType = streamType
+	}
+}
+
+// WithFtpOptionSchedule An option for SetFtp to set when uploads happen
+func WithFtpOptionSchedule(schedule models.Schedule) FtpOption {
+	return func(ftp *models.NetworkFTP) {
+		ftp.Schedule = schedule
+	}
+}
+
+type PushOption func(push *models.NetworkPush)
+
+// WithPushOptionBase An option for SetPush to start from a complete configuration instead of the camera's current one
+func WithPushOptionBase(base *models.NetworkPush) PushOption {
+	return func(push *models.NetworkPush) {
+		if base == nil {
+			return
+		}
+
+		*push = *base
+	}
+}
+
+// WithPushOptionSchedule An option for SetPush to set when push notifications are sent
+func WithPushOptionSchedule(schedule models.Schedule) PushOption {
+	return func(push *models.NetworkPush) {
+		push.Schedule = schedule
+	}
+}
+
+type LocalLinkOption func(link *models.NetworkGeneral)
+
+// WithLocalLinkOptionDhcp An option for SetLocalLink to get the address from a DHCP server
+func WithLocalLinkOptionDhcp() LocalLinkOption {
+	return func(link *models.NetworkGeneral) {
+		link.Type = enum.LINK_TYPE_DHCP.Value()
+	}
+}
+
+// WithLocalLinkOptionStatic An option for SetLocalLink to use a static address, e.g.
+// WithLocalLinkOptionStatic("192.168.1.20", "255.255.255.0", "192.168.1.1")
+func WithLocalLinkOptionStatic(ip string, mask string, gateway string) LocalLinkOption {
+	return func(link *models.NetworkGeneral) {
+		link.Type = enum.LINK_TYPE_STATIC.Value()
+		link.Static = models.NetworkGeneralStatic{
+			Gateway: gateway,
+			Ip:      ip,
+			Mask:    mask,
+		}
+	}
+}
+
+// WithLocalLinkOptionDns An option for SetLocalLink to use fixed DNS servers, dns2 may be empty
+func WithLocalLinkOptionDns(dns1 string, dns2 string) LocalLinkOption {
+	return func(link *models.NetworkGeneral) {
+		link.Dns = models.NetworkGeneralDns{
+			Auto: 0,
+			Dns1: dns1,
+			Dns2: dns2,
+		}
+	}
+}
+
+// WithLocalLinkOptionAutoDns An option for SetLocalLink to take the DNS servers from DHCP
+func WithLocalLinkOptionAutoDns() LocalLinkOption {
+	return func(link *models.NetworkGeneral) {
+		link.Dns.Auto = 1
+	}
+}
@@ -62,3 +62,79 @@ func WithOsdOptionWatermark(enable enum.Toggle) OsdOption {
 		o.Watermark = enable
 	}
 }
+
+// WithOsdOptionOsdTimeDateFormat Set the OSD date format
+func WithOsdOptionOsdTimeDateFormat(format enum.OsdDateFormat) OsdOption {
+	return func(o *models.Osd) {
+		o.OsdTime.DateFmt = format.Value()
+	}
+}
+
+// WithOsdOptionOsdTimeHourFormat Set the OSD time to 12 or 24 hour format
+func WithOsdOptionOsdTimeHourFormat(format enum.OsdHourFormat) OsdOption {
+	return func(o *models.Osd) {
+		hourFmt := format.Value()
+		o.OsdTime.HourFmt = &hourFmt
+	}
+}
+
+// WithOsdOptionOsdTimeLanguage Set the language used for the OSD date (e.g. "English")
+func WithOsdOptionOsdTimeLanguage(language string) OsdOption {
+	return func(o *models.Osd) {
+		o.OsdTime.Language = language
+	}
+}
+
+// WithOsdOptionOsdTime Set the complete OSD time layout in one go
+func WithOsdOptionOsdTime(osdTime models.OsdTime) OsdOption {
+	return func(o *models.Osd) {
+		o.OsdTime = osdTime
+	}
+}
+
+// WithOsdOptionOsdChannel Set the complete OSD channel layout in one go
+func WithOsdOptionOsdChannel(osdChannel models.OsdChannel) OsdOption {
+	return func(o *models.Osd) {
+		o.OsdChannel = osdChannel
+	}
+}
+
+// WithOsdOptionFontSize Set the OSD font size
+func WithOsdOptionFontSize(size enum.OsdFontSize) OsdOption {
+	return func(o *models.Osd) {
+		o.FontSize = size.Value()
+	}
+}
+
+// WithOsdOptionCustomText Add or replace a custom text overlay at the given position.
+// Only supported on some camera models.
+func WithOsdOptionCustomText(text string, position enum.OsdPosition) OsdOption {
+	return func(o *models.Osd) {
+		for i, custom := range o.OsdCustom {
+			if custom.Pos == position.Value() {
+				o.OsdCustom[i].Enable = enum.Enabled
+				o.OsdCustom[i].Text = text
+				return
+			}
+		}
+
+		o.OsdCustom = append(o.OsdCustom, models.OsdCustom{
+			Enable: enum.Enabled,
+			Text:   text,
+			Pos:    position.Value(),
+		})
+	}
+}
+
+// WithOsdOptionBase Start from an existing OSD, typically the one returned by GetOSD.
+// Must be passed before any other option as it replaces the whole OSD.
+func WithOsdOptionBase(base *models.Osd) OsdOption {
+	return func(o *models.Osd) {
+		if base == nil {
+			return
+		}
+
+		*o = *base
+		o.OsdCustom = append([]models.OsdCustom(nil), base.OsdCustom...)
+	}
+}
@@ -2,6 +2,7 @@ package options
 
 import (
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"time"
 )
 
@@ -16,21 +17,43 @@ func WithDeviceNameOptionName(name string) DeviceNameOption {
 
 type DeviceTimeOption func(osd *models.DeviceTime)
 
-// WithDeviceTimeOptionTime sets the camera device time.
+// WithDeviceTimeOptionTime sets the camera device time to t's wall clock and the time zone to the standard offset of
+// t's location. Use WithDeviceTimeOptionDst for its daylight saving.
 func WithDeviceTimeOptionTime(t time.Time) DeviceTimeOption {
 	return func(dt *models.DeviceTime) {
-		_, offSet := t.Zone()
-		t := models.TimeInformation{
-			Day:      t.Day(),
-			Hour:     t.Hour(),
-			HourFmt:  0,
-			Min:      t.Minute(),
-			Mon:      int(t.Month()),
-			Sec:      t.Second(),
-			TimeFmt:  "DD/MM/YYYY",
-			TimeZone: offSet,
-			Year:     t.Year(),
+		timeInformation := models.TimeInformation{
+			HourFmt: 0,
+			TimeFmt: "DD/MM/YYYY",
 		}
-		dt.Time = &t
+		timeInformation.SetTime(t)
+		dt.Time = &timeInformation
+	}
+}
+
+// WithDeviceTimeOptionDst sets the camera's daylight saving rule, see models.DstFromLocation to build it from a zone
+func WithDeviceTimeOptionDst(dst *models.DstInformation) DeviceTimeOption {
+	return func(dt *models.DeviceTime) {
+		dt.Dst = dst
+	}
+}
+
+type AutoMaintOption func(autoMaint *models.AutoMaint)
+
+// WithAutoMaintOptionEnable An option for SetAutoMaint to enable or disable the maintenance reboot
+func WithAutoMaintOptionEnable(enable enum.Toggle) AutoMaintOption {
+	return func(am *models.AutoMaint) {
+		am.Enable = enable
+	}
+}
+
+// WithAutoMaintOptionSchedule An option for SetAutoMaint to reboot on the day at hour:minute camera time, it also
+// enables the maintenance reboot
+func WithAutoMaintOptionSchedule(day enum.MaintenanceDay, hour int, minute int) AutoMaintOption {
+	return func(am *models.AutoMaint) {
+		am.Enable = enum.Enabled
+		am.WeekDay = day.Value()
+		am.Hour = hour
+		am.Min = minute
+		am.Sec = 0
 	}
 }
@@ -0,0 +1,147 @@
+package reolinkapi
+
+import (
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"golang.org/x/net/context"
+	"net"
+	"strconv"
+	"time"
+)
+
+type rebootOptions struct {
+	pollInterval time.Duration
+	checkRtsp    bool
+}
+
+type OptionReboot func(*rebootOptions)
+
+// Set how often the rebooting camera is polled
+// Default: 2 seconds
+func RebootOptionPollInterval(interval time.Duration) OptionReboot {
+	return func(r *rebootOptions) {
+		r.pollInterval = interval
+	}
+}
+
+// Only return once the camera's RTSP port accepts connections again, the port is read before rebooting.
+// The port is dialled directly, it is not reached through the network options' proxy.
+// Default: false
+func RebootOptionCheckRtsp() OptionReboot {
+	return func(r *rebootOptions) {
+		r.checkRtsp = true
+	}
+}
+
+// RebootAndWait reboots the camera and returns once it is usable again: the HTTP API is seen going down, the camera
+// is logged into again and, with RebootOptionCheckRtsp, the RTSP port is listening.
+// Cancel ctx, or give it a deadline, to bound the wait. A request already sent to the camera when ctx is done is
+// waited for, so the login cannot replace the handler's token once RebootAndWait returned.
+func (c *Camera) RebootAndWait(ctx context.Context, opts ...OptionReboot) error {
+	options := &rebootOptions{
+		pollInterval: 2 * time.Second,
+	}
+
+	for _, op := range opts {
+		op(options)
+	}
+
+	handler := c.RestHandler
+	rtspAddress := ""
+
+	if options.checkRtsp {
+		networkPort, err := c.GetNetworkPort()(handler)
+
+		if err != nil {
+			return fmt.Errorf("reading the RTSP port: %w", err)
+		}
+
+		if networkPort == nil || networkPort.RtspEnable != enum.Enabled {
+			return fmt.Errorf("RTSP is disabled on the camera")
+		}
+
+		rtspAddress = net.JoinHostPort(handler.GetHost(), strconv.Itoa(networkPort.RtspPort))
+	}
+
+	if _, err := c.RebootCamera()(handler); err != nil {
+		return err
+	}
+
+	// the old token does not survive the reboot, a camera that comes back between two polls refuses it
+	err := c.poll(ctx, options.pollInterval, func() (bool, error) {
+		_, err := c.GetDeviceInformation()(handler)
+
+		return err != nil, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("camera did not go down after the reboot: %w", err)
+	}
+
+	err = c.poll(ctx, options.pollInterval, func() (bool, error) {
+		_, err := c.Login()(handler)
+
+		return err == nil, err
+	})
+
+	if err != nil {
+		return fmt.Errorf("camera did not come back after the reboot: %w", err)
+	}
+
+	if rtspAddress == "" {
+		return nil
+	}
+
+	var dialer net.Dialer
+
+	err = c.poll(ctx, options.pollInterval, func() (bool, error) {
+		conn, err := dialer.DialContext(ctx, "tcp", rtspAddress)
+
+		if err != nil {
+			return false, err
+		}
+
+		conn.Close()
+
+		return true, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("RTSP port %s is not listening after the reboot: %w", rtspAddress, err)
+	}
+
+	return nil
+}
+
+// call try every interval until it is done. Once ctx is done its error is returned, wrapped with the last error try
+// gave. try is not started once ctx is done, one that is running is waited for.
+func (c *Camera) poll(ctx context.Context, interval time.Duration, try func() (bool, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("last error: %v: %w", lastErr, ctx.Err())
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if ctx.Err() != nil {
+			continue
+		}
+
+		done, err := try()
+
+		if done {
+			return nil
+		}
+
+		lastErr = err
+	}
+}
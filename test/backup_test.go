@@ -0,0 +1,454 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/backup"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// configCamera keeps every setting under the name the camera uses for it, Get<Name> returns it and Set<Name>
+// replaces it. Commands listed in refuse are answered with an error.
+type configCamera struct {
+	mu       sync.Mutex
+	settings map[string]json.RawMessage
+	refuse   map[string]bool
+	sets     []string
+	// checked on login when set
+	password string
+}
+
+func newConfigCamera(t *testing.T) *configCamera {
+	schedule := models.Schedule{Enable: enum.Enabled, Table: strings.Repeat("1", 168)}
+
+	settings := map[string]interface{}{
+		"DevInfo": &models.DeviceInformation{Model: "RLC-410", FirmwareVersion: "v3.0.0.136_20121101",
+			Name: "garden"},
+		"Image": &models.Image{Brightness: 128, Contrast: 128, Hue: 128, Saturation: 128, Sharpness: 128},
+		"Isp": &models.Isp{AntiFlicker: "Outdoor", Exposure: "Auto", DayNight: "Auto", BackLight: "DynamicRangeControl",
+			WhiteBalance: "Auto", Gain: models.MinMax{Min: 1, Max: 62}, Shutter: models.MinMax{Min: 0, Max: 125}},
+		"Osd": &models.Osd{OsdChannel: models.OsdChannel{Enable: enum.Enabled, Name: "garden", Pos: "Lower Right"},
+			OsdTime: models.OsdTime{Enable: enum.Enabled, Pos: "Top Center"}},
+		"Mask": &models.MaskData{Enable: enum.Enabled, Area: []models.MaskArea{{
+			Block:  models.MaskAreaBlock{Height: 10, Width: 20, X: 5, Y: 5},
+			Screen: models.MaskAreaScreen{Height: 360, Width: 640},
+		}}},
+		"Enc": &models.Encoding{Audio: enum.Enabled,
+			MainStream: models.RecordingMainStream{BitRate: 6144, FrameRate: 25, Profile: "High", Size: "2560*1440"},
+			SubStream:  models.RecordingSubStream{BitRate: 160, FrameRate: 15, Profile: "High", Size: "640*360"}},
+		"Rec": &models.Recording{Overwrite: enum.Enabled, PostRecord: "15 Seconds", PreRecord: enum.Enabled,
+			Schedule: schedule},
+		"Ntp":  &models.NetworkNTP{Enable: enum.Enabled, Interval: 1440, Port: 123, Server: "pool.ntp.org"},
+		"Ddns": &models.NetworkDDNS{Type: "no-ip", Domain: "garden.ddns.net", Username: "ddns", Password: "ddns-secret"},
+		"Email": &models.NetworkEmail{SmtpServer: "smtp.example.com", SmtpPort: 465, SSL: enum.Enabled,
+			Username: "camera@example.com", Password: "smtp-secret", Addr1: "me@example.com", Schedule: schedule},
+		"Ftp": &models.NetworkFTP{Server: "ftp.example.com", Port: 21, Username: "ftp", Password: "ftp-secret",
+			Schedule: schedule},
+		"Push":    &models.NetworkPush{Schedule: schedule},
+		"Upnp":    &models.NetworkUpnp{Enable: enum.Disabled},
+		"P2p":     &models.NetworkP2p{Enable: enum.Enabled, Uid: "95270000ABCDEFGH"},
+		"NetPort": &models.NetworkPort{HttpEnable: enum.Enabled, HttpPort: 80, MediaPort: 9000, RtspPort: 554},
+		"User":    []*models.User{{Username: "admin", Level: "admin"}, {Username: "viewer", Level: "guest"}},
+	}
+
+	cc := &configCamera{settings: map[string]json.RawMessage{}, refuse: map[string]bool{"GetAlarm": true}}
+
+	for name, setting := range settings {
+		data, err := json.Marshal(setting)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cc.settings[name] = data
+	}
+
+	return cc
+}
+
+func (cc *configCamera) get(t *testing.T, name string, v interface{}) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if err := json.Unmarshal(cc.settings[name], v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (cc *configCamera) put(t *testing.T, name string, v interface{}) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc.settings[name] = data
+}
+
+func registerMockConfigCamera(cc *configCamera) {
+	registerMockConfigCameraAt("127.0.0.1", cc)
+}
+
+func registerMockConfigCameraAt(host string, cc *configCamera) {
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://%s/cgi-bin/api.cgi", host),
+		func(req *http.Request) (*http.Response, error) {
+			cc.mu.Lock()
+			defer cc.mu.Unlock()
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if err := json.Unmarshal(data, &reqData); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+
+			if cc.refuse[cmd] {
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   cmd,
+					"code":  1,
+					"error": map[string]interface{}{"detail": "not support", "rspCode": -9},
+				}})
+			}
+
+			value := map[string]interface{}{"rspCode": 200}
+
+			switch {
+			case cmd == "Login":
+				var user struct {
+					Password string `json:"password"`
+				}
+
+				_ = json.Unmarshal(reqData[0].Param["User"], &user)
+
+				if cc.password != "" && user.Password != cc.password {
+					return httpmock.NewStringResponse(500, "username or password incorrect"), nil
+				}
+
+				value = map[string]interface{}{"Token": map[string]interface{}{"Name": "12345", "LeaseTime": 3600}}
+			case cmd == "AddUser":
+				var user models.User
+
+				if err := json.Unmarshal(reqData[0].Param["User"], &user); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				var users []models.User
+				_ = json.Unmarshal(cc.settings["User"], &users)
+				users = append(users, models.User{Username: user.Username, Level: user.Level})
+				cc.settings["User"], _ = json.Marshal(users)
+				cc.sets = append(cc.sets, cmd)
+			case strings.HasPrefix(cmd, "Get"):
+				name := strings.TrimPrefix(cmd, "Get")
+				value = map[string]interface{}{name: cc.settings[name]}
+			case strings.HasPrefix(cmd, "Set"):
+				name := strings.TrimPrefix(cmd, "Set")
+				cc.settings[name] = reqData[0].Param[name]
+				cc.sets = append(cc.sets, cmd)
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func newConfigCameraClient(t *testing.T, cc *configCamera) *reolinkapi.Camera {
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerMockConfigCamera(cc)
+
+	return camera
+}
+
+func TestBackup_BackupAndRestore(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	cc := newConfigCamera(t)
+	camera := newConfigCameraClient(t, cc)
+
+	document, err := backup.Backup(camera)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if document.Version != backup.FORMAT_VERSION || document.Device.Model != "RLC-410" ||
+		document.Device.FirmwareVersion != "v3.0.0.136_20121101" {
+		t.Errorf("unexpected document header %+v", document.Device)
+	}
+
+	if len(document.Unsupported) != 1 || document.Unsupported[0] != "alarm" {
+		t.Errorf("expected only the alarm to be unsupported, got %v", document.Unsupported)
+	}
+
+	if document.Osd == nil || document.Mask == nil || document.Encoding == nil || document.Recording == nil ||
+		document.Image == nil || document.Isp == nil || document.Network == nil || document.Network.Port == nil {
+		t.Fatalf("document is missing sections: %+v", document)
+	}
+
+	if document.Network.Email.Password != "" || document.Network.Ftp.Password != "" ||
+		document.Network.Ddns.Password != "" {
+		t.Errorf("document holds passwords")
+	}
+
+	if len(document.Users) != 2 {
+		t.Errorf("expected 2 users got %v", document.Users)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := document.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buffer.String(), "secret") {
+		t.Errorf("written document holds passwords:\n%s", buffer.String())
+	}
+
+	document, err = backup.Read(&buffer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// change the camera after the backup
+	var osd models.Osd
+	cc.get(t, "Osd", &osd)
+	osd.OsdChannel.Name = "renamed"
+	cc.put(t, "Osd", &osd)
+
+	var ntp models.NetworkNTP
+	cc.get(t, "Ntp", &ntp)
+	ntp.Server = "time.example.com"
+	cc.put(t, "Ntp", &ntp)
+
+	cc.put(t, "User", []*models.User{{Username: "admin", Level: "admin"}})
+
+	cc.mu.Lock()
+	cc.sets = nil
+	cc.mu.Unlock()
+
+	report, err := backup.Restore(camera, document, backup.RestoreOptionUserPassword("viewer", "viewer-password"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Err() != nil {
+		t.Fatal(report.Err())
+	}
+
+	sort.Strings(report.Changed)
+
+	if strings.Join(report.Changed, ",") != "network.ntp,osd,users" {
+		t.Errorf("unexpected changed sections %v", report.Changed)
+	}
+
+	if strings.Join(cc.sets, ",") != "AddUser,SetOsd,SetNtp" {
+		t.Errorf("unexpected commands %v", cc.sets)
+	}
+
+	cc.get(t, "Osd", &osd)
+	cc.get(t, "Ntp", &ntp)
+
+	if osd.OsdChannel.Name != "garden" || ntp.Server != "pool.ntp.org" {
+		t.Errorf("camera was not restored: %q %q", osd.OsdChannel.Name, ntp.Server)
+	}
+
+	t.Log(report)
+}
+
+func TestBackup_RestoreFailures(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	cc := newConfigCamera(t)
+	camera := newConfigCameraClient(t, cc)
+
+	document, err := backup.Backup(camera)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the camera keeps its email password when the document holds none
+	document.Network.Email.SmtpPort = 587
+	document.Mask.Enable = enum.Disabled
+	cc.refuse["SetMask"] = true
+	cc.put(t, "User", []*models.User{{Username: "admin", Level: "admin"}})
+
+	report, err := backup.Restore(camera, document)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := map[string]bool{}
+
+	for _, f := range report.Failed {
+		failed[f.Section] = true
+	}
+
+	if len(report.Failed) != 2 || !failed["mask"] || !failed["users"] {
+		t.Errorf("expected mask and users to fail, got %v", report.Failed)
+	}
+
+	var email models.NetworkEmail
+	cc.get(t, "Email", &email)
+
+	if email.SmtpPort != 587 || email.Password != "smtp-secret" {
+		t.Errorf("unexpected email settings %+v", email)
+	}
+
+	document.Device.Model = "RLC-520"
+
+	if _, err := backup.Restore(camera, document); err == nil {
+		t.Error("expected a document of another model to be refused")
+	}
+
+	if _, err := backup.Restore(camera, document, backup.RestoreOptionIgnoreModel(),
+		backup.RestoreOptionSections("network")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBackup_CompareAndApply(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	cc := newConfigCamera(t)
+	camera := newConfigCameraClient(t, cc)
+
+	desired, err := backup.ReadDesired(strings.NewReader(`{
+		"device": {"model": "RLC-410"},
+		"osd": {"osdChannel": {"name": "front door"}},
+		"image": {"bright": 128},
+		"network": {
+			"ntp": {"server": "pool.ntp.org", "enable": true},
+			"email": {"password": "new-secret"}
+		},
+		"users": [{"userName": "viewer", "level": "admin"}, {"userName": "installer", "level": "guest"}]
+	}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := backup.Compare(camera, desired)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cc.sets) != 0 {
+		t.Errorf("compare changed the camera: %v", cc.sets)
+	}
+
+	var changes []string
+
+	for _, change := range diff.Changes() {
+		changes = append(changes, change.String())
+	}
+
+	expected := []string{
+		`~ users.viewer.level: "guest" -> "admin"`,
+		`+ users.installer: "guest"`,
+		`~ osd.osdChannel.name: "garden" -> "front door"`,
+		`~ network.email.password: *** -> ***`,
+	}
+
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected changes\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(changes, "\n"))
+	}
+
+	sort.Strings(diff.Unchanged)
+
+	if strings.Join(diff.Unchanged, ",") != "image,network.ntp" {
+		t.Errorf("unexpected unchanged sections %v", diff.Unchanged)
+	}
+
+	// a misspelt field fails its section instead of being ignored
+	typo, err := backup.Compare(camera, backup.Desired{"osd": map[string]interface{}{"osdChanel": "x"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(typo.Failed) != 1 || typo.Failed[0].Section != "osd" {
+		t.Errorf("expected the osd section to fail, got %v", typo.Failed)
+	}
+
+	// so is a misspelt section
+	for _, typo := range []backup.Desired{
+		{"netwrok": map[string]interface{}{"ntp": map[string]interface{}{"enable": true}}},
+		{"network": map[string]interface{}{"nntp": map[string]interface{}{"enable": true}}},
+	} {
+		if _, err := backup.Compare(camera, typo); err == nil {
+			t.Errorf("expected %v to be refused", typo)
+		}
+	}
+
+	diff, err = backup.Compare(camera, desired, backup.RestoreOptionSections("osd"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := backup.Apply(camera, diff)
+
+	if report.Err() != nil || strings.Join(report.Changed, ",") != "osd" {
+		t.Errorf("unexpected report %v", report)
+	}
+
+	if strings.Join(cc.sets, ",") != "SetOsd" {
+		t.Errorf("expected only the osd to be sent, got %v", cc.sets)
+	}
+
+	var osd models.Osd
+	cc.get(t, "Osd", &osd)
+
+	if osd.OsdChannel.Name != "front door" || osd.OsdChannel.Pos != "Lower Right" || osd.OsdTime.Enable != enum.Enabled {
+		t.Errorf("unexpected osd %+v", osd)
+	}
+
+	t.Log(diff)
+}
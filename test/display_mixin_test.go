@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
 	"io/ioutil"
@@ -105,7 +106,7 @@ func registerMockGetMask() {
 					},
 				},
 				Channel: 0,
-				Enable:  false,
+				Enable:  enum.Disabled,
 			}
 
 			generalData := map[string]interface{}{
@@ -253,3 +254,114 @@ func TestDisplayMixin_SetOSD(t *testing.T) {
 	t.Logf("SetOSD %v", ok)
 
 }
+
+func registerMockUpdateOsd() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if reqData[0].Cmd == "GetOsd" {
+				osdInfo := &models.Osd{
+					BgColor: enum.Enabled,
+					Channel: 0,
+					OsdChannel: models.OsdChannel{
+						Enable: enum.Enabled,
+						Name:   "FarRight",
+						Pos:    enum.LOWER_RIGHT.Value(),
+					},
+					OsdTime: models.OsdTime{
+						Enable: enum.Enabled,
+						Pos:    enum.LOWER_LEFT.Value(),
+					},
+					Watermark: enum.Enabled,
+				}
+
+				generalData := map[string]interface{}{
+					"cmd":  "GetOsd",
+					"code": 0,
+					"value": map[string]interface{}{
+						"Osd": osdInfo,
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
+			if reqData[0].Cmd == "SetOsd" {
+				var osdData *models.Osd
+
+				err = json.Unmarshal(reqData[0].Param["Osd"], &osdData)
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				// everything but the date format must be left untouched
+				if osdData.OsdChannel.Name != "FarRight" || osdData.BgColor != enum.Enabled ||
+					osdData.Watermark != enum.Enabled || osdData.OsdTime.Pos != enum.LOWER_LEFT.Value() ||
+					osdData.OsdTime.DateFmt != enum.DATE_FORMAT_YYYY_MM_DD.Value() {
+					return httpmock.NewStringResponse(500, "osd fields were reset"), nil
+				}
+
+				generalData := map[string]interface{}{
+					"cmd":  "SetOsd",
+					"code": 0,
+					"value": map[string]interface{}{
+						"rspCode": 200,
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
+			return httpmock.NewStringResponse(500, "Operation Unknown"), nil
+		},
+	)
+}
+
+func TestDisplayMixin_UpdateOSD(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockUpdateOsd()
+
+	ok, err := camera.UpdateOSD(options.WithOsdOptionOsdTimeDateFormat(enum.DATE_FORMAT_YYYY_MM_DD))(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Logf("UpdateOSD %v", ok)
+}
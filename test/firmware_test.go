@@ -0,0 +1,350 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/firmware"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// firmwareCamera plays a camera through an upgrade: it installs the uploaded image in steps, drops off the network
+// while rebooting and comes back with the new version
+type firmwareCamera struct {
+	mu sync.Mutex
+
+	version    string
+	newVersion string
+	image      string
+	prepared   bool
+	uploaded   string
+	progress   []int
+	rebooting  int
+	commands   []string
+}
+
+func (fc *firmwareCamera) respond(cmd string, value map[string]interface{}) (*http.Response, error) {
+	return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+		"cmd":   cmd,
+		"code":  0,
+		"value": value,
+	}})
+}
+
+func registerMockFirmware(fc *firmwareCamera) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+			fc.mu.Lock()
+			defer fc.mu.Unlock()
+
+			if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+				fc.commands = append(fc.commands, "Upgrade")
+
+				if !fc.prepared {
+					return httpmock.NewStringResponse(500, "upgrade was not prepared"), nil
+				}
+
+				file, _, err := req.FormFile("file")
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				data, err := ioutil.ReadAll(file)
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				fc.uploaded = string(data)
+
+				return fc.respond("Upgrade", map[string]interface{}{"rspCode": 200})
+			}
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if err := json.Unmarshal(data, &reqData); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+			fc.commands = append(fc.commands, cmd)
+
+			switch cmd {
+			case "Login":
+				if fc.rebooting > 0 {
+					fc.rebooting--
+					return nil, fmt.Errorf("connection refused")
+				}
+
+				return fc.respond(cmd, map[string]interface{}{
+					"Token": map[string]interface{}{"Name": "12345", "LeaseTime": 3600},
+				})
+			case "GetDevInfo":
+				return fc.respond(cmd, map[string]interface{}{
+					"DevInfo": map[string]interface{}{"firmVer": fc.version},
+				})
+			case "UpgradePrepare":
+				fc.prepared = true
+				return fc.respond(cmd, map[string]interface{}{"rspCode": 200})
+			case "UpgradeStatus":
+				if fc.uploaded == "" {
+					return httpmock.NewStringResponse(500, "nothing uploaded"), nil
+				}
+
+				if len(fc.progress) == 0 {
+					fc.version = fc.newVersion
+					return nil, fmt.Errorf("connection reset by peer")
+				}
+
+				percent := fc.progress[0]
+				fc.progress = fc.progress[1:]
+
+				if len(fc.progress) == 0 {
+					// the camera reboots into the new image
+					fc.version = fc.newVersion
+				}
+
+				return fc.respond(cmd, map[string]interface{}{
+					"Status": map[string]interface{}{"Persent": percent, "code": 0},
+				})
+			}
+
+			return fc.respond(cmd, map[string]interface{}{"rspCode": 200})
+		},
+	)
+}
+
+func newFirmwareCamera(t *testing.T, fc *firmwareCamera) *reolinkapi.Camera {
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerMockFirmware(fc)
+
+	return camera
+}
+
+func TestFirmware_Upgrade(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	fc := &firmwareCamera{
+		version:    "v3.0.0.136_20121101",
+		newVersion: "v3.0.0.660_21110804",
+		progress:   []int{0, 50, 100},
+		rebooting:  2,
+	}
+
+	camera := newFirmwareCamera(t, fc)
+
+	var stages []firmware.Stage
+	var percents []int
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := firmware.Upgrade(ctx, camera, "IPC_51516M5M.pak", strings.NewReader("firmware image"),
+		firmware.UpgradeOptionTargetVersion("v3.0.0.660_21110804"),
+		firmware.UpgradeOptionPollInterval(time.Millisecond),
+		firmware.UpgradeOptionProgress(func(stage firmware.Stage, percent int) {
+			if len(stages) == 0 || stages[len(stages)-1] != stage {
+				stages = append(stages, stage)
+			}
+
+			if stage == firmware.STAGE_INSTALL {
+				percents = append(percents, percent)
+			}
+		}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Upgraded || result.PreviousVersion != "v3.0.0.136_20121101" || result.Version != "v3.0.0.660_21110804" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if fc.uploaded != "firmware image" {
+		t.Errorf("camera received %q", fc.uploaded)
+	}
+
+	expectedStages := []firmware.Stage{firmware.STAGE_UPLOAD, firmware.STAGE_INSTALL, firmware.STAGE_REBOOT,
+		firmware.STAGE_VERIFY}
+
+	if fmt.Sprint(stages) != fmt.Sprint(expectedStages) {
+		t.Errorf("expected stages %v got %v", expectedStages, stages)
+	}
+
+	if fmt.Sprint(percents) != fmt.Sprint([]int{0, 50, 100}) {
+		t.Errorf("unexpected install progress %v", percents)
+	}
+
+	t.Logf("upgraded %s -> %s", result.PreviousVersion, result.Version)
+}
+
+func TestFirmware_UpgradeAlreadyCurrent(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	fc := &firmwareCamera{version: "v3.0.0.660_21110804"}
+
+	camera := newFirmwareCamera(t, fc)
+
+	result, err := firmware.Upgrade(context.Background(), camera, "IPC_51516M5M.pak",
+		strings.NewReader("firmware image"), firmware.UpgradeOptionTargetVersion("v3.0.0.660_21110804"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Upgraded {
+		t.Errorf("expected the upgrade to be skipped, got %+v", result)
+	}
+
+	if fmt.Sprint(fc.commands) != "[GetDevInfo]" {
+		t.Errorf("expected only the version check, camera received %v", fc.commands)
+	}
+}
+
+func TestFirmware_UpgradeRebootWithoutProgress(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	// the camera reboots straight after the upload, before reporting any progress
+	fc := &firmwareCamera{
+		version:    "v3.0.0.136_20121101",
+		newVersion: "v3.0.0.660_21110804",
+		rebooting:  2,
+	}
+
+	camera := newFirmwareCamera(t, fc)
+
+	result, err := firmware.Upgrade(context.Background(), camera, "IPC_51516M5M.pak",
+		strings.NewReader("firmware image"), firmware.UpgradeOptionPollInterval(time.Millisecond))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Upgraded || result.Version != "v3.0.0.660_21110804" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestFirmware_UpgradeSameVersion(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	// the camera reboots but comes back with the version it had
+	fc := &firmwareCamera{
+		version:    "v3.0.0.136_20121101",
+		newVersion: "v3.0.0.136_20121101",
+		progress:   []int{50, 100},
+		rebooting:  2,
+	}
+
+	camera := newFirmwareCamera(t, fc)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := firmware.Upgrade(context.Background(), camera, "IPC_51516M5M.pak",
+			strings.NewReader("firmware image"), firmware.UpgradeOptionPollInterval(time.Millisecond))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the upgrade to fail")
+		}
+
+		t.Log(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("upgrade kept waiting for a camera back on its old version")
+	}
+}
+
+func TestFirmware_UpgradeTimeout(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	// the camera never goes down after installing
+	fc := &firmwareCamera{
+		version:    "v3.0.0.136_20121101",
+		newVersion: "v3.0.0.136_20121101",
+		progress:   []int{100, 100},
+	}
+
+	camera := newFirmwareCamera(t, fc)
+
+	_, err := firmware.Upgrade(context.Background(), camera, "IPC_51516M5M.pak",
+		strings.NewReader("firmware image"), firmware.UpgradeOptionPollInterval(time.Millisecond),
+		firmware.UpgradeOptionTimeout(50*time.Millisecond))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the reboot wait to time out, got %v", err)
+	}
+}
+
+func TestFirmware_UpgradeCancelled(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	// the camera never comes back from the reboot
+	fc := &firmwareCamera{
+		version:    "v3.0.0.136_20121101",
+		newVersion: "v3.0.0.136_20121101",
+		progress:   []int{100},
+		rebooting:  1 << 30,
+	}
+
+	camera := newFirmwareCamera(t, fc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := firmware.Upgrade(ctx, camera, "IPC_51516M5M.pak", strings.NewReader("firmware image"),
+		firmware.UpgradeOptionPollInterval(time.Millisecond))
+
+	if err == nil {
+		t.Fatal("expected the upgrade to time out")
+	}
+
+	if ctx.Err() == nil {
+		t.Errorf("upgrade returned before the deadline: %v", err)
+	}
+
+	t.Log(err)
+}
@@ -0,0 +1,211 @@
+package test
+
+import (
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/fleet"
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
+	"os"
+	"strings"
+	"testing"
+)
+
+const fleetYaml = `
+credentials:
+  office:
+    username: admin
+    passwordEnv: FLEET_TEST_PASSWORD
+defaults:
+  network:
+    ntp: {enable: true, server: pool.ntp.org}
+cameras:
+  - name: garden
+    host: 127.0.0.1
+    credentials: office
+    osd: {osdChannel: {name: Garden}}
+    users:
+      - {userName: viewer, level: guest}
+      - {userName: installer, level: admin, passwordEnv: FLEET_TEST_INSTALLER}
+    schedules:
+      recording: mon-fri 08-18
+  - name: porch
+    host: 127.0.0.2
+    credentials: office
+    osd: {osdChannel: {name: garden}}
+  - name: shed
+    host: 127.0.0.3
+    credentials: office
+`
+
+func TestFleet_PlanAndApply(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	os.Setenv("FLEET_TEST_PASSWORD", "office-password")
+	os.Setenv("FLEET_TEST_INSTALLER", "installer-password")
+
+	defer os.Unsetenv("FLEET_TEST_PASSWORD")
+	defer os.Unsetenv("FLEET_TEST_INSTALLER")
+
+	garden := newConfigCamera(t)
+	garden.password = "office-password"
+	registerMockConfigCameraAt("127.0.0.1", garden)
+
+	porch := newConfigCamera(t)
+	porch.password = "office-password"
+	registerMockConfigCameraAt("127.0.0.2", porch)
+
+	// the shed camera does not answer
+
+	f, err := fleet.Load(strings.NewReader(fleetYaml))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := f.Plan(context.Background(), fleet.FleetOptionConcurrency(2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(garden.sets) != 0 || len(porch.sets) != 0 {
+		t.Errorf("plan changed the cameras: %v %v", garden.sets, porch.sets)
+	}
+
+	if plan.Empty() || plan.Cameras[0].Err != nil || plan.Cameras[1].Err != nil || plan.Cameras[2].Err == nil {
+		t.Fatalf("unexpected plan\n%s", plan)
+	}
+
+	var paths []string
+
+	for _, change := range plan.Cameras[0].Diff.Changes() {
+		paths = append(paths, change.Path)
+	}
+
+	if strings.Join(paths, ",") != "users.installer,osd.osdChannel.name,recording.schedule.table" {
+		t.Errorf("unexpected changes for garden %v", paths)
+	}
+
+	if !plan.Cameras[1].Diff.Empty() {
+		t.Errorf("expected no changes for porch\n%s", plan.Cameras[1].Diff)
+	}
+
+	if !strings.Contains(plan.String(), "Plan: 1 to change, 1 unchanged, 1 failed.") {
+		t.Errorf("unexpected plan summary\n%s", plan)
+	}
+
+	result := f.Apply(context.Background(), plan)
+
+	if result.Cameras[0].Err != nil || result.Cameras[0].Report.Err() != nil {
+		t.Errorf("garden failed: %v %v", result.Cameras[0].Err, result.Cameras[0].Report)
+	}
+
+	if result.Err() == nil || !strings.Contains(result.Err().Error(), "shed") ||
+		strings.Contains(result.Err().Error(), "garden") {
+		t.Errorf("expected only shed to fail, got %v", result.Err())
+	}
+
+	if strings.Join(garden.sets, ",") != "AddUser,SetOsd,SetRec" || len(porch.sets) != 0 {
+		t.Errorf("unexpected commands %v %v", garden.sets, porch.sets)
+	}
+
+	var recording models.Recording
+	garden.get(t, "Rec", &recording)
+
+	table, _ := fleet.ParseSchedule("mon-fri 08-18")
+
+	if recording.Schedule.Table != table || recording.Schedule.Enable != 1 {
+		t.Errorf("unexpected recording schedule %+v", recording.Schedule)
+	}
+
+	t.Log("\n" + plan.String())
+}
+
+func TestFleet_Load(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"unknown setting":       "cameras: [{host: 127.0.0.1, osdd: {}}]",
+		"undefined credentials": "cameras: [{host: 127.0.0.1, credentials: nope}]",
+		"missing host":          "cameras: [{name: garden}]",
+		"duplicate camera":      "cameras: [{host: 127.0.0.1}, {host: 127.0.0.1}]",
+		"bad schedule":          "cameras: [{host: 127.0.0.1, schedules: {recording: 'someday 08-18'}}]",
+		"unknown top level key": "camera: []",
+	} {
+		if _, err := fleet.Load(strings.NewReader(yaml)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else {
+			t.Logf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestFleet_LoadSharedDefaults(t *testing.T) {
+	yaml := `
+defaults:
+  network:
+    ntp: {enable: true}
+cameras:
+  - name: a
+    host: 127.0.0.1
+    schedules:
+      email: mon-fri 08-18
+  - name: b
+    host: 127.0.0.2
+`
+
+	f, err := fleet.Load(strings.NewReader(yaml))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := f.Cameras[0].Desired["network"].(map[string]interface{})
+	b, _ := f.Cameras[1].Desired["network"].(map[string]interface{})
+
+	if _, ok := a["email"]; !ok {
+		t.Errorf("camera a lost its email schedule: %v", a)
+	}
+
+	if _, ok := b["email"]; ok {
+		t.Errorf("camera a's email schedule leaked into camera b: %v", b)
+	}
+
+	if _, ok := b["ntp"]; !ok {
+		t.Errorf("camera b lost the default ntp settings: %v", b)
+	}
+}
+
+func TestFleet_ParseSchedule(t *testing.T) {
+	table, err := fleet.ParseSchedule("mon-fri 08-18", "sat 22:00-02:00")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hours := func(day int) string {
+		return table[day*24 : day*24+24]
+	}
+
+	if hours(1) != "000000001111111111000000" || hours(5) != hours(1) {
+		t.Errorf("unexpected weekday hours %s", hours(1))
+	}
+
+	if hours(6) != "000000000000000000000011" || hours(0) != "110000000000000000000000" {
+		t.Errorf("unexpected weekend hours %s %s", hours(6), hours(0))
+	}
+
+	if always, _ := fleet.ParseSchedule("always"); always != strings.Repeat("1", 168) {
+		t.Errorf("unexpected table for always")
+	}
+
+	if never, _ := fleet.ParseSchedule("never"); never != strings.Repeat("0", 168) {
+		t.Errorf("unexpected table for never")
+	}
+
+	for _, bad := range []string{"mon", "mon 8-25", "funday 01-02", "mon 08:30-09"} {
+		if _, err := fleet.ParseSchedule(bad); err == nil {
+			t.Errorf("expected %q to be refused", bad)
+		}
+	}
+}
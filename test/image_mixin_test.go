@@ -3,6 +3,7 @@ package test
 import (
 	"encoding/json"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
@@ -37,6 +38,34 @@ func registerMockSetAdvancedImage() {
 				return httpmock.NewStringResponse(500, err.Error()), nil
 			}
 
+			if reqData[0].Cmd == "GetIsp" {
+				generalData := map[string]interface{}{
+					"cmd":  "GetIsp",
+					"code": 0,
+					"value": map[string]interface{}{
+						"Isp": &models.Isp{
+							Channel:      0,
+							AntiFlicker:  "Outdoor",
+							Exposure:     "Auto",
+							Gain:         models.MinMax{Min: 1, Max: 62},
+							Shutter:      models.MinMax{Min: 0, Max: 125},
+							BlueGain:     128,
+							RedGain:      128,
+							WhiteBalance: "Auto",
+							DayNight:     "Auto",
+							BackLight:    "DynamicRangeControl",
+							Blc:          128,
+							Drc:          128,
+							Rotation:     0,
+							Mirroring:    0,
+							Nr3d:         1,
+						},
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
 			var advImgSet map[string]interface{}
 
 			err = json.Unmarshal(reqData[0].Param["Isp"], &advImgSet)
@@ -86,6 +115,25 @@ func registerMockSetImageSettings() {
 				return httpmock.NewStringResponse(500, err.Error()), nil
 			}
 
+			if reqData[0].Cmd == "GetImage" {
+				generalData := map[string]interface{}{
+					"cmd":  "GetImage",
+					"code": 0,
+					"value": map[string]interface{}{
+						"Image": &models.Image{
+							Brightness: 110,
+							Channel:    0,
+							Contrast:   120,
+							Hue:        128,
+							Saturation: 140,
+							Sharpness:  128,
+						},
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
 			var imgSet map[string]interface{}
 
 			err = json.Unmarshal(reqData[0].Param["Image"], &imgSet)
@@ -170,3 +218,246 @@ func TestImageMixin_SetImageSettings(t *testing.T) {
 
 	t.Logf("SetImageSettings %v", ok)
 }
+
+func registerMockGetImageSettings() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		httpmock.NewStringResponder(200, `[{
+			"cmd": "GetImage",
+			"code": 0,
+			"range": {"Image": {
+				"bright": {"max": 255, "min": 0},
+				"channel": 0,
+				"contrast": {"max": 255, "min": 0},
+				"hue": {"max": 255, "min": 0},
+				"saturation": {"max": 255, "min": 0},
+				"sharpen": {"max": 255, "min": 0}
+			}},
+			"value": {"Image": {
+				"bright": 110, "channel": 0, "contrast": 120, "hue": 128, "saturation": 140, "sharpen": 128
+			}}
+		}]`),
+	)
+}
+
+func registerMockGetAdvanceImageSettings() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		httpmock.NewStringResponder(200, `[{
+			"cmd": "GetIsp",
+			"code": 0,
+			"range": {"Isp": {
+				"antiFlicker": ["Outdoor", "50HZ", "60HZ", "Off"],
+				"backLight": ["DynamicRangeControl", "BackLightControl", "Off"],
+				"blc": {"max": 255, "min": 0},
+				"blueGain": {"max": 255, "min": 0},
+				"channel": 0,
+				"dayNight": ["Auto", "Color", "Black&White"],
+				"drc": {"max": 255, "min": 0},
+				"exposure": ["Auto", "LowNoise", "Anti-Smearing", "Manual"],
+				"gain": {"max": {"max": 100, "min": 1}, "min": {"max": 100, "min": 1}},
+				"mirroring": "boolean",
+				"nr3d": "boolean",
+				"redGain": {"max": 255, "min": 0},
+				"rotation": "boolean",
+				"shutter": {"max": {"max": 125, "min": 0}, "min": {"max": 125, "min": 0}},
+				"whiteBalance": ["Auto", "Manual"]
+			}},
+			"value": {"Isp": {
+				"antiFlicker": "50HZ",
+				"backLight": "BackLightControl",
+				"blc": 128,
+				"blueGain": 128,
+				"channel": 0,
+				"dayNight": "Black&White",
+				"drc": 128,
+				"exposure": "Manual",
+				"gain": {"max": 62, "min": 1},
+				"mirroring": 1,
+				"nr3d": 1,
+				"redGain": 128,
+				"rotation": 0,
+				"shutter": {"max": 125, "min": 0},
+				"whiteBalance": "Auto"
+			}}
+		}]`),
+	)
+}
+
+func TestImageMixin_GetImageSettings(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockGetImageSettings()
+
+	imageSettings, err := camera.GetImageSettings()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imageSettings.Image.Brightness != 110 || imageSettings.Range == nil ||
+		imageSettings.Range.Brightness.Max != 255 {
+		t.Errorf("unexpected image settings %+v %+v", imageSettings.Image, imageSettings.Range)
+	}
+
+	t.Logf("GetImageSettings %+v %+v", imageSettings.Image, imageSettings.Range)
+}
+
+func TestImageMixin_GetAdvanceImageSettings(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockGetAdvanceImageSettings()
+
+	ispSettings, err := camera.GetAdvanceImageSettings()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exposure, err := ispSettings.Isp.ExposureMode()
+
+	if err != nil || exposure != enum.EXPOSURE_MANUAL {
+		t.Errorf("expected manual exposure, got %v %v", exposure, err)
+	}
+
+	dayNight, err := ispSettings.Isp.DayNightMode()
+
+	if err != nil || dayNight != enum.DAY_NIGHT_BLACK_WHITE {
+		t.Errorf("expected black & white day night mode, got %v %v", dayNight, err)
+	}
+
+	if ispSettings.Range == nil || len(ispSettings.Range.Exposure) != 4 || ispSettings.Range.Gain.Max.Max != 100 {
+		t.Errorf("unexpected isp range %+v", ispSettings.Range)
+	}
+
+	t.Logf("GetAdvanceImageSettings %+v %+v", ispSettings.Isp, ispSettings.Range)
+}
+
+func TestImageMixin_SetImageSettingsOutOfRange(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockGetImageSettings()
+
+	ok, err := camera.SetImageSettings(api.ImageOptionBrightness(300))(camera.RestHandler)
+
+	if _, isValidationError := err.(*models.ValidationError); !isValidationError {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+
+	t.Logf("SetImageSettings %v %v", ok, err)
+}
+
+// some firmware leaves fields such as blc, drc, gain and shutter out of the range
+func registerMockPartialIspRange() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+			var reqData []*struct {
+				Cmd string `json:"cmd"`
+			}
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if err := json.Unmarshal(data, &reqData); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if reqData[0].Cmd == "SetIsp" {
+				return httpmock.NewStringResponse(200, `[{"cmd": "SetIsp", "code": 0, "value": {"rspCode": 200}}]`),
+					nil
+			}
+
+			return httpmock.NewStringResponse(200, `[{
+				"cmd": "GetIsp",
+				"code": 0,
+				"range": {"Isp": {
+					"antiFlicker": ["Outdoor", "50HZ", "60HZ", "Off"],
+					"channel": 0,
+					"exposure": ["Auto", "Manual"]
+				}},
+				"value": {"Isp": {
+					"antiFlicker": "50HZ",
+					"blc": 128,
+					"channel": 0,
+					"drc": 128,
+					"exposure": "Manual",
+					"gain": {"max": 62, "min": 1},
+					"shutter": {"max": 125, "min": 0}
+				}}
+			}]`), nil
+		},
+	)
+}
+
+func TestImageMixin_SetAdvanceImageSettingsPartialRange(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerMockPartialIspRange()
+
+	ok, err := camera.SetAdvanceImageSettings(api.ImageAdvancedOptionBlc(200), api.ImageAdvancedOptionDrc(64),
+		api.ImageAdvancedOptionGainMax(80))(camera.RestHandler)
+
+	if err != nil || !ok {
+		t.Errorf("fields without a range should not be validated, got %v %v", ok, err)
+	}
+
+	_, err = camera.SetAdvanceImageSettings(api.ImageAdvancedOptionAntiFlicker(enum.ANTI_FLICKER_OFF),
+		api.ImageAdvancedOptionExposure(enum.EXPOSURE_LOW_NOISE))(camera.RestHandler)
+
+	if _, isValidationError := err.(*models.ValidationError); !isValidationError {
+		t.Errorf("fields with a range should still be validated, got %v", err)
+	}
+}
@@ -0,0 +1,361 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/imageprofile"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// a camera keeping its image and isp settings in memory, SetImage is rejected when failImage is true
+func registerMockImageProfileCamera(image *models.Image, isp *models.Isp, failImage bool) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		imageProfileResponder(image, isp, failImage))
+}
+
+func imageProfileResponder(image *models.Image, isp *models.Isp, failImage bool) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+
+		type ReqData struct {
+			Cmd    string                     `json:"cmd"`
+			Action int                        `json:"action"`
+			Param  map[string]json.RawMessage `json:"param"`
+		}
+
+		var reqData []*ReqData
+
+		data, err := ioutil.ReadAll(req.Body)
+
+		if err != nil {
+			return httpmock.NewStringResponse(500, err.Error()), nil
+		}
+
+		err = json.Unmarshal(data, &reqData)
+
+		if err != nil {
+			return httpmock.NewStringResponse(500, err.Error()), nil
+		}
+
+		setOk := map[string]interface{}{
+			"cmd":  reqData[0].Cmd,
+			"code": 0,
+			"value": map[string]interface{}{
+				"rspCode": 200,
+			},
+		}
+
+		switch reqData[0].Cmd {
+		case "GetImage":
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   "GetImage",
+				"code":  0,
+				"value": map[string]interface{}{"Image": image},
+			}})
+		case "GetIsp":
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   "GetIsp",
+				"code":  0,
+				"value": map[string]interface{}{"Isp": isp},
+			}})
+		case "SetImage":
+			if failImage {
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "SetImage",
+					"code":  1,
+					"error": map[string]interface{}{"detail": "set config failed", "rspCode": -6},
+				}})
+			}
+
+			if err := json.Unmarshal(reqData[0].Param["Image"], image); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{setOk})
+		case "SetIsp":
+			if err := json.Unmarshal(reqData[0].Param["Isp"], isp); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{setOk})
+		}
+
+		return httpmock.NewStringResponse(500, "Operation Unknown"), nil
+	}
+}
+
+func nightProfile() *imageprofile.Profile {
+	return &imageprofile.Profile{
+		Name: "night",
+		Image: []api.OptionImageSetting{
+			api.ImageOptionBrightness(150),
+		},
+		Advanced: []api.OptionAdvancedImageSetting{
+			api.ImageAdvancedOptionDayNight(enum.DAY_NIGHT_BLACK_WHITE),
+			api.ImageAdvancedOptionGainMax(100),
+		},
+	}
+}
+
+func TestImageProfile_Apply(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	image := &models.Image{Brightness: 128, Contrast: 128, Hue: 128, Saturation: 128, Sharpness: 128}
+	isp := &models.Isp{DayNight: "Auto", Exposure: "Auto", Gain: models.MinMax{Min: 1, Max: 62}}
+
+	registerMockImageProfileCamera(image, isp, false)
+
+	profiles := imageprofile.NewProfiles(camera, nightProfile())
+
+	err = profiles.Apply(context.Background(), "night")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if image.Brightness != 150 || image.Contrast != 128 || isp.DayNight != "Black&White" || isp.Gain.Max != 100 ||
+		isp.Exposure != "Auto" {
+		t.Errorf("profile not applied correctly %+v %+v", image, isp)
+	}
+
+	if profiles.Active() != "night" {
+		t.Errorf("expected night to be the active profile, got %q", profiles.Active())
+	}
+
+	if err := profiles.Apply(context.Background(), "day"); err == nil {
+		t.Errorf("expected an error applying an unknown profile")
+	}
+}
+
+func TestImageProfile_ApplyCancelled(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	image := &models.Image{Brightness: 128, Contrast: 128, Hue: 128, Saturation: 128, Sharpness: 128}
+	isp := &models.Isp{DayNight: "Auto", Exposure: "Auto", Gain: models.MinMax{Min: 1, Max: 62}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	respond := imageProfileResponder(image, isp, false)
+	var commands []string
+
+	// the caller gives up once the advanced image settings are sent
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+			cmd := req.URL.Query().Get("cmd")
+			commands = append(commands, cmd)
+
+			if cmd == "SetIsp" {
+				cancel()
+			}
+
+			return respond(req)
+		})
+
+	profiles := imageprofile.NewProfiles(camera, nightProfile())
+
+	err = profiles.Apply(ctx, "night")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the profile to be cancelled, got %v", err)
+	}
+
+	if image.Brightness != 128 || isp.DayNight != "Auto" || isp.Gain.Max != 62 {
+		t.Errorf("camera was left with part of the profile %+v %+v", image, isp)
+	}
+
+	for _, cmd := range commands {
+		if cmd == "SetImage" {
+			t.Errorf("image settings were sent after cancelling: %v", commands)
+		}
+	}
+
+	commands = nil
+
+	if err := profiles.Apply(ctx, "night"); !errors.Is(err, context.Canceled) || len(commands) != 0 {
+		t.Errorf("a cancelled apply should not reach the camera, got %v %v", err, commands)
+	}
+}
+
+func TestImageProfile_ApplyRollback(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	image := &models.Image{Brightness: 128, Contrast: 128, Hue: 128, Saturation: 128, Sharpness: 128}
+	isp := &models.Isp{DayNight: "Auto", Exposure: "Auto", Gain: models.MinMax{Min: 1, Max: 62}}
+
+	registerMockImageProfileCamera(image, isp, true)
+
+	profiles := imageprofile.NewProfiles(camera, nightProfile())
+
+	err = profiles.Apply(context.Background(), "night")
+
+	if err == nil {
+		t.Fatal("expected the profile to fail")
+	}
+
+	if isp.DayNight != "Auto" || isp.Gain.Max != 62 {
+		t.Errorf("advanced image settings were not rolled back %+v", isp)
+	}
+
+	if profiles.Active() != "" {
+		t.Errorf("expected no active profile, got %q", profiles.Active())
+	}
+
+	t.Logf("Apply %v", err)
+}
+
+func TestImageProfile_SunriseSunset(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+
+	if err != nil {
+		t.Skip(err)
+	}
+
+	sunrise, sunset, err := imageprofile.SunriseSunset(time.Date(2020, 6, 21, 0, 0, 0, 0, london), 51.5074, -0.1278)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// published times are 04:43 and 21:21 BST
+	expectedSunrise := time.Date(2020, 6, 21, 4, 43, 0, 0, london)
+	expectedSunset := time.Date(2020, 6, 21, 21, 21, 0, 0, london)
+
+	if d := sunrise.Sub(expectedSunrise); d < -3*time.Minute || d > 3*time.Minute {
+		t.Errorf("sunrise %s is too far from %s", sunrise, expectedSunrise)
+	}
+
+	if d := sunset.Sub(expectedSunset); d < -3*time.Minute || d > 3*time.Minute {
+		t.Errorf("sunset %s is too far from %s", sunset, expectedSunset)
+	}
+
+	// no sunset in Tromsø in the middle of summer
+	_, _, err = imageprofile.SunriseSunset(time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553)
+
+	if err == nil {
+		t.Errorf("expected polar day to be reported")
+	}
+}
+
+func TestImageProfile_Scheduler(t *testing.T) {
+	profiles := imageprofile.NewProfiles(nil,
+		&imageprofile.Profile{Name: "day"},
+		&imageprofile.Profile{Name: "dusk"},
+		nightProfile(),
+	)
+
+	scheduler, err := imageprofile.NewScheduler(profiles,
+		imageprofile.SchedulerOptionLocation(time.UTC),
+		imageprofile.SchedulerOptionPosition(51.5074, -0.1278),
+		imageprofile.SchedulerOptionSunrise(0, "day"),
+		imageprofile.SchedulerOptionSunset(-30*time.Minute, "dusk"),
+		imageprofile.SchedulerOptionAt(23, 0, "night"),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noon := time.Date(2020, 6, 21, 12, 0, 0, 0, time.UTC)
+
+	current, err := scheduler.Current(noon)
+
+	if err != nil || current != "day" {
+		t.Errorf("expected day at noon, got %q %v", current, err)
+	}
+
+	at, next, err := scheduler.Next(noon)
+
+	if err != nil || next != "dusk" || at.Hour() != 19 {
+		t.Errorf("expected dusk around 19:50 UTC, got %q at %s %v", next, at, err)
+	}
+
+	current, err = scheduler.Current(time.Date(2020, 6, 22, 1, 0, 0, 0, time.UTC))
+
+	if err != nil || current != "night" {
+		t.Errorf("expected night after midnight, got %q %v", current, err)
+	}
+
+	_, err = imageprofile.NewScheduler(profiles, imageprofile.SchedulerOptionSunset(0, "night"))
+
+	if err == nil {
+		t.Errorf("expected an error for a sunset switch without a position")
+	}
+}
+
+func TestImageProfile_SchedulerDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+
+	if err != nil {
+		t.Skipf("time zone Europe/Berlin is not available: %v", err)
+	}
+
+	profiles := imageprofile.NewProfiles(nil, &imageprofile.Profile{Name: "day"}, nightProfile())
+
+	scheduler, err := imageprofile.NewScheduler(profiles,
+		imageprofile.SchedulerOptionLocation(berlin),
+		imageprofile.SchedulerOptionAt(7, 0, "day"),
+		imageprofile.SchedulerOptionAt(22, 0, "night"),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// clocks go forward on 2021-03-28 and back on 2021-10-31, both days are 23 and 25 hours long
+	for _, day := range []int{28, 31} {
+		month := time.March
+
+		if day == 31 {
+			month = time.October
+		}
+
+		at, next, err := scheduler.Next(time.Date(2021, month, day, 1, 0, 0, 0, berlin))
+
+		if err != nil || next != "day" || at.Hour() != 7 || at.Minute() != 0 {
+			t.Errorf("expected day at 07:00 on %s %d, got %q at %s %v", month, day, next, at, err)
+		}
+
+		at, next, err = scheduler.Next(at)
+
+		if err != nil || next != "night" || at.Hour() != 22 {
+			t.Errorf("expected night at 22:00 on %s %d, got %q at %s %v", month, day, next, at, err)
+		}
+	}
+}
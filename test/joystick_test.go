@@ -0,0 +1,132 @@
+package test
+
+import (
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/joystick"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
+	"testing"
+	"time"
+)
+
+func TestJoystick_Quantise(t *testing.T) {
+	j := joystick.NewJoystick(nil)
+
+	tests := []struct {
+		vector   joystick.Vector
+		expected joystick.Command
+	}{
+		{joystick.Vector{}, joystick.STOP},
+		{joystick.Vector{Pan: 0.1, Tilt: -0.05}, joystick.STOP},
+		{joystick.Vector{Pan: 1}, joystick.Command{Operation: "Right", Speed: 64}},
+		{joystick.Vector{Pan: -1}, joystick.Command{Operation: "Left", Speed: 64}},
+		{joystick.Vector{Tilt: 1}, joystick.Command{Operation: "Up", Speed: 64}},
+		{joystick.Vector{Tilt: -2}, joystick.Command{Operation: "Down", Speed: 64}},
+		{joystick.Vector{Pan: 0.7, Tilt: 0.7}, joystick.Command{Operation: "RightUp", Speed: 64}},
+		{joystick.Vector{Pan: -0.5, Tilt: -0.5}, joystick.Command{Operation: "LeftDown", Speed: 46}},
+		{joystick.Vector{Pan: 0.95, Tilt: 0.2}, joystick.Command{Operation: "Right", Speed: 64}},
+		{joystick.Vector{Pan: 0.16}, joystick.Command{Operation: "Right", Speed: 1}},
+		{joystick.Vector{Zoom: 0.5}, joystick.Command{Operation: "ZoomInc", Speed: 60}},
+		{joystick.Vector{Pan: 0.2, Zoom: -0.8}, joystick.Command{Operation: "ZoomDec", Speed: 60}},
+	}
+
+	for _, test := range tests {
+		if got := j.Quantise(test.vector); got != test.expected {
+			t.Errorf("%+v: expected %s, got %s", test.vector, test.expected, got)
+		}
+	}
+}
+
+func TestJoystick_Run(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	j := joystick.NewJoystick(camera,
+		joystick.JoystickOptionMinInterval(50*time.Millisecond),
+		joystick.JoystickOptionIdleTimeout(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := j.Run(ctx)
+
+	// a wobbling stick at 200 Hz, every vector maps onto the same command
+	for i := 0; i < 20; i++ {
+		j.Set(joystick.Vector{Pan: 1, Tilt: 0.01 * float64(i%3)})
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// quick changes within the rate limit, only the last one is sent
+	j.Set(joystick.Vector{Tilt: 1})
+	j.Set(joystick.Vector{Tilt: -1})
+	j.Set(joystick.Vector{Zoom: 1})
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+
+	for err := range errChan {
+		t.Error(err)
+	}
+
+	expected := []string{"Right", "ZoomInc", "Stop"}
+	ops := recorder.operations()
+
+	if len(ops) != len(expected) {
+		t.Fatalf("expected operations %v, got %v", expected, ops)
+	}
+
+	for i := range ops {
+		if ops[i] != expected[i] {
+			t.Fatalf("expected operations %v, got %v", expected, ops)
+		}
+	}
+}
+
+func TestJoystick_IdleTimeout(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	j := joystick.NewJoystick(camera,
+		joystick.JoystickOptionMinInterval(time.Millisecond),
+		joystick.JoystickOptionIdleTimeout(30*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errChan := j.Run(ctx)
+
+	j.Set(joystick.Vector{Pan: -1})
+	time.Sleep(100 * time.Millisecond)
+
+	ops := recorder.operations()
+
+	if len(ops) != 2 || ops[0] != "Left" || ops[1] != "Stop" {
+		t.Errorf("expected the camera to stop once the input went quiet, got %v", ops)
+	}
+
+	cancel()
+
+	for err := range errChan {
+		t.Error(err)
+	}
+}
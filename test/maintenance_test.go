@@ -0,0 +1,159 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// maintenanceCamera keeps the auto maintenance schedule and records the commands it receives
+type maintenanceCamera struct {
+	autoMaint json.RawMessage
+	commands  []string
+}
+
+func registerMockMaintenance(mc *maintenanceCamera) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+			mc.commands = append(mc.commands, cmd)
+
+			value := map[string]interface{}{"rspCode": 200}
+
+			switch cmd {
+			case "GetAutoMaint":
+				value = map[string]interface{}{"AutoMaint": mc.autoMaint}
+			case "SetAutoMaint":
+				mc.autoMaint = reqData[0].Param["AutoMaint"]
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func TestSystemMixin_Restore(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mc := &maintenanceCamera{}
+	registerMockMaintenance(mc)
+
+	if ok, err := camera.Restore()(camera.RestHandler); err == nil || ok {
+		t.Errorf("Restore without confirmation should fail, got %v %v", ok, err)
+	}
+
+	if len(mc.commands) != 0 {
+		t.Fatalf("Restore without confirmation sent %v", mc.commands)
+	}
+
+	ok, err := camera.Restore(api.RestoreOptionConfirm())(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok || len(mc.commands) != 1 || mc.commands[0] != "Restore" {
+		t.Errorf("Restore sent %v, ok %v", mc.commands, ok)
+	}
+}
+
+func TestSystemMixin_AutoMaint(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mc := &maintenanceCamera{
+		autoMaint: json.RawMessage(`{"enable":0,"weekDay":"Everyday","hour":2,"min":0,"sec":0}`),
+	}
+	registerMockMaintenance(mc)
+
+	autoMaint, err := camera.GetAutoMaint()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day, err := autoMaint.Day()
+
+	if err != nil || day != enum.MAINTENANCE_EVERYDAY || autoMaint.Enable != enum.Disabled {
+		t.Errorf("GetAutoMaint returned %+v", autoMaint)
+	}
+
+	ok, err := camera.SetAutoMaint(options.WithAutoMaintOptionSchedule(enum.MAINTENANCE_SUNDAY, 3, 30))(
+		camera.RestHandler)
+
+	if err != nil || !ok {
+		t.Fatalf("SetAutoMaint %v %v", ok, err)
+	}
+
+	var sent models.AutoMaint
+
+	if err := json.Unmarshal(mc.autoMaint, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := models.AutoMaint{Enable: enum.Enabled, WeekDay: "Sunday", Hour: 3, Min: 30}
+
+	if sent != expected {
+		t.Errorf("SetAutoMaint sent %+v, expected %+v", sent, expected)
+	}
+
+	sets := len(mc.commands)
+
+	if _, err := camera.SetAutoMaint(options.WithAutoMaintOptionSchedule(enum.MAINTENANCE_MONDAY, 25, 0))(
+		camera.RestHandler); err == nil {
+		t.Error("SetAutoMaint should refuse hour 25")
+	}
+
+	if mc.commands[len(mc.commands)-1] != "GetAutoMaint" || len(mc.commands) != sets+1 {
+		t.Errorf("an invalid schedule was sent: %v", mc.commands)
+	}
+}
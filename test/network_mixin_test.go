@@ -2,13 +2,20 @@ package test
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func registerMockSetNetworkPort() {
@@ -36,6 +43,30 @@ func registerMockSetNetworkPort() {
 				return httpmock.NewStringResponse(500, err.Error()), nil
 			}
 
+			if reqData[0].Cmd == "GetNetPort" {
+				generalData := map[string]interface{}{
+					"cmd":  "GetNetPort",
+					"code": 0,
+					"value": map[string]interface{}{
+						"NetPort": &models.NetworkPort{
+							HttpEnable:  enum.Enabled,
+							HttpPort:    8080,
+							HttpsEnable: enum.Enabled,
+							HttpsPort:   443,
+							MediaPort:   9000,
+							OnvifEnable: enum.Enabled,
+							OnvifPort:   8000,
+							RtmpEnable:  enum.Disabled,
+							RtmpPort:    1935,
+							RtspEnable:  enum.Enabled,
+							RtspPort:    554,
+						},
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
 			var networkPort map[string]interface{}
 
 			err = json.Unmarshal(reqData[0].Param["NetPort"], &networkPort)
@@ -46,6 +77,11 @@ func registerMockSetNetworkPort() {
 
 			log.Printf("received NetPort: %v", networkPort)
 
+			// the ports that were not passed as options must keep the camera's current values
+			if networkPort["httpPort"] != float64(8080) || networkPort["rtspEnable"] != float64(1) {
+				return httpmock.NewStringResponse(500, "network ports were reset"), nil
+			}
+
 			generalData := map[string]interface{}{
 				"cmd":  "SetNetPort",
 				"code": 0,
@@ -230,7 +266,10 @@ func registerMockScanWifi() {
 				return httpmock.NewStringResponse(500, err.Error()), nil
 			}
 
-			scanWifi := &models.ScanWifi{}
+			scanWifi := models.ScanWifi{
+				{Ssid: "home", Signal: 82, Encrypt: "WPA2PSK", Channel: 6},
+				{Ssid: "guest", Signal: 35, Encrypt: "NONE", Channel: 11},
+			}
 
 			generalData := map[string]interface{}{
 				"cmd":  "ScanWifi",
@@ -273,7 +312,7 @@ func registerMockGetNetworkDDNS() {
 
 			networkDDNS := &models.NetworkDDNS{
 				Domain:   "",
-				Enable:   false,
+				Enable:   enum.Disabled,
 				Password: "",
 				Type:     "no-ip",
 				Username: "",
@@ -319,7 +358,7 @@ func registerMockGetNetworkNTP() {
 			}
 
 			networkNtp := &models.NetworkNTP{
-				Enable:   true,
+				Enable:   enum.Enabled,
 				Interval: 1440,
 				Port:     123,
 				Server:   "ntp.moos.xyz",
@@ -374,12 +413,12 @@ func registerMockGetNetworkEmail() {
 				Interval:   "5 Minute",
 				Nickname:   "",
 				Schedule: models.Schedule{
-					Enable: true,
+					Enable: enum.Enabled,
 					Table:  "111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111",
 				},
 				SmtpPort:   465,
 				SmtpServer: "smtp.gmail.com",
-				SSL:        true,
+				SSL:        enum.Enabled,
 			}
 
 			generalData := map[string]interface{}{
@@ -424,14 +463,14 @@ func registerMockGetNetworkFTP() {
 			networkFtp := &models.NetworkFTP{
 				Username:  "",
 				Password:  "",
-				Anonymous: false,
+				Anonymous: enum.Disabled,
 				Interval:  30,
 				MaxSize:   100,
 				Mode:      0,
 				Port:      21,
 				RemoteDir: "",
 				Schedule: models.Schedule{
-					Enable: true,
+					Enable: enum.Enabled,
 					Table:  "111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111",
 				},
 				Server:     "",
@@ -479,7 +518,7 @@ func registerMockGetNetworkPush() {
 
 			networkPush := &models.NetworkPush{
 				Schedule: models.Schedule{
-					Enable: true,
+					Enable: enum.Enabled,
 					Table:  "111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111",
 				},
 			}
@@ -516,7 +555,7 @@ func TestNetworkMixin_SetNetworkPort(t *testing.T) {
 
 	registerMockSetNetworkPort()
 
-	ok, err := camera.SetNetworkPort()(camera.RestHandler)
+	ok, err := camera.SetNetworkPort(options.WithNetworkPortOptionOnvifEnable(enum.Disabled))(camera.RestHandler)
 
 	if err != nil {
 		t.Error(err)
@@ -605,6 +644,11 @@ func TestNetworkMixin_ScanWifi(t *testing.T) {
 		t.Error(err)
 	}
 
+	if len(scanWifiInfo) != 2 || scanWifiInfo[0].Ssid != "home" || scanWifiInfo[0].Signal != 82 ||
+		scanWifiInfo[1].Encrypt != "NONE" || scanWifiInfo[1].Channel != 11 {
+		t.Errorf("unexpected scan result %+v", scanWifiInfo)
+	}
+
 	t.Logf("ScanWifi %v", scanWifiInfo)
 }
 
@@ -768,13 +812,836 @@ func TestNetworkMixin_GetNetworkStatus(t *testing.T) {
 		t.Error(err)
 	}
 
-	registerMockGetNetworkGeneral()
+	registerMockNetworkStatus("WiFi")
+
+	status, err := camera.GetNetworkStatus()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.ActiveLink != "WiFi" || status.Type != "DHCP" || status.Ip != "192.168.255.58" ||
+		status.Gateway != "192.168.255.1" || status.Mac != "EC:71:DB:AA:59:CF" || status.WifiSignal != 61 ||
+		status.NetThroughput != 2048 {
+		t.Errorf("unexpected network status %+v", status)
+	}
+
+	registerMockNetworkStatus("LAN")
+
+	status, err = camera.GetNetworkStatus()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.ActiveLink != "LAN" || status.WifiSignal != 0 {
+		t.Errorf("unexpected network status %+v", status)
+	}
+
+	t.Logf("GetNetworkStatus %+v", status)
+}
+
+// a camera on activeLink, GetWifiSignal fails while it is not on WiFi
+func registerMockNetworkStatus(activeLink string) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			var value map[string]interface{}
+
+			switch reqData[0].Cmd {
+			case "GetLocalLink":
+				response, err := ioutil.ReadFile("../examples/response/GetNetworkGeneral.json")
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				return httpmock.NewBytesResponse(200,
+					[]byte(strings.Replace(string(response), `"LAN"`, fmt.Sprintf("%q", activeLink), 1))), nil
+			case "GetPerformance":
+				value = map[string]interface{}{"Performance": models.DevicePerformanceInformation{
+					CodecRate:     2154,
+					CpuUsed:       14,
+					NetThroughput: 2048,
+				}}
+			case "GetWifiSignal":
+				if activeLink != "WiFi" {
+					return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+						"cmd":   "GetWifiSignal",
+						"code":  1,
+						"error": map[string]interface{}{"detail": "not support", "rspCode": -9},
+					}})
+				}
+
+				value = map[string]interface{}{"wifiSignal": 61}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   reqData[0].Cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+// answers the network getters with real camera responses and records the settings sent by the setters
+func registerMockNetworkSettings(sent map[string]json.RawMessage) {
+	responses := map[string]string{
+		"GetNtp":   "../examples/response/GetNetworkNTP.json",
+		"GetDdns":  "../examples/response/GetNetworkDDNS.json",
+		"GetEmail": "../examples/response/GetNetworkEmail.json",
+		"GetFtp":   "../examples/response/GetNetworkFtp.json",
+		"GetPush":  "../examples/response/GetNetworkPush.json",
+	}
+
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if file, ok := responses[reqData[0].Cmd]; ok {
+				response, err := ioutil.ReadFile(file)
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				return httpmock.NewBytesResponse(200, response), nil
+			}
+
+			for _, value := range reqData[0].Param {
+				sent[reqData[0].Cmd] = value
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}})
+		},
+	)
+}
+
+func TestNetworkMixin_SetNetworkSettings(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	sent := map[string]json.RawMessage{}
+	registerMockNetworkSettings(sent)
+
+	handler := camera.RestHandler
+
+	if _, err := camera.SetNtp(options.WithNtpOptionServer("pool.ntp.org", 123))(handler); err != nil {
+		t.Fatal(err)
+	}
+
+	var ntp models.NetworkNTP
+	_ = json.Unmarshal(sent["SetNtp"], &ntp)
+
+	if ntp.Server != "pool.ntp.org" || ntp.Interval != 1440 || ntp.Enable != enum.Enabled {
+		t.Errorf("unexpected ntp settings sent %+v", ntp)
+	}
+
+	_, err = camera.SetDdns(options.WithDdnsOptionEnable(enum.Enabled),
+		options.WithDdnsOptionDomain("cam.example.org"),
+		options.WithDdnsOptionCredentials("user", "secret"))(handler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ddns models.NetworkDDNS
+	_ = json.Unmarshal(sent["SetDdns"], &ddns)
+
+	if ddns.Type != "no-ip" || ddns.Domain != "cam.example.org" || ddns.Username != "user" {
+		t.Errorf("unexpected ddns settings sent %+v", ddns)
+	}
+
+	_, err = camera.SetEmail(options.WithEmailOptionCredentials("me@example.org", "secret"),
+		options.WithEmailOptionRecipients("alarm@example.org"))(handler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var email models.NetworkEmail
+	_ = json.Unmarshal(sent["SetEmail"], &email)
+
+	if email.SmtpServer != "smtp.gmail.com" || email.SmtpPort != 465 || email.SSL != enum.Enabled ||
+		email.Username != "me@example.org" || email.Addr1 != "alarm@example.org" || email.Interval != "5 Minutes" {
+		t.Errorf("unexpected email settings sent %+v", email)
+	}
+
+	_, err = camera.SetFtp(options.WithFtpOptionServer("ftp.example.org", 2121),
+		options.WithFtpOptionRemoteDir("/cam"))(handler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ftp models.NetworkFTP
+	_ = json.Unmarshal(sent["SetFtp"], &ftp)
+
+	if ftp.Server != "ftp.example.org" || ftp.Port != 2121 || ftp.RemoteDir != "/cam" || ftp.MaxSize != 100 {
+		t.Errorf("unexpected ftp settings sent %+v", ftp)
+	}
+
+	_, err = camera.SetPush(options.WithPushOptionSchedule(models.Schedule{Enable: enum.Enabled, Table: "1"}))(handler)
+
+	if err == nil {
+		t.Error("expected a schedule without 168 hours to be rejected")
+	}
+
+	weekdays := ""
+	for day := 0; day < 7; day++ {
+		if day == 0 || day == 6 {
+			weekdays += strings.Repeat("0", 24)
+		} else {
+			weekdays += strings.Repeat("1", 24)
+		}
+	}
+
+	_, err = camera.SetPush(options.WithPushOptionSchedule(models.Schedule{Enable: enum.Enabled, Table: weekdays}))(handler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var push models.NetworkPush
+	_ = json.Unmarshal(sent["SetPush"], &push)
+
+	if push.Schedule.Table != weekdays {
+		t.Errorf("unexpected push settings sent %+v", push)
+	}
+}
+
+// a stand-in server greeting every connection like an SMTP or FTP server would, returns its host and port
+func startDeliveryStandIn(t *testing.T, greeting string) (string, int, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write([]byte(greeting + "\r\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	return addr.IP.String(), addr.Port, func() { _ = listener.Close() }
+}
+
+// a camera which answers TestEmail and TestFtp by connecting to the configured server and waiting for its greeting
+func registerMockDeliveryTest() {
+	responses := map[string]string{
+		"GetEmail": "../examples/response/GetNetworkEmail.json",
+		"GetFtp":   "../examples/response/GetNetworkFtp.json",
+	}
+
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+
+			if file, ok := responses[cmd]; ok {
+				response, err := ioutil.ReadFile(file)
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				return httpmock.NewBytesResponse(200, response), nil
+			}
+
+			var server string
+
+			switch cmd {
+			case "TestEmail":
+				var email models.NetworkEmail
+				_ = json.Unmarshal(reqData[0].Param["Email"], &email)
+				server = fmt.Sprintf("%s:%d", email.SmtpServer, email.SmtpPort)
+			case "TestFtp":
+				var ftp models.NetworkFTP
+				_ = json.Unmarshal(reqData[0].Param["Ftp"], &ftp)
+				server = fmt.Sprintf("%s:%d", ftp.Server, ftp.Port)
+			}
+
+			greeting := ""
+			conn, err := net.DialTimeout("tcp", server, time.Second)
+
+			if err == nil {
+				buf := make([]byte, 64)
+				n, _ := conn.Read(buf)
+				greeting = string(buf[:n])
+				_ = conn.Close()
+			}
+
+			if !strings.HasPrefix(greeting, "220") {
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   cmd,
+					"code":  1,
+					"error": map[string]interface{}{"detail": "test failed", "rspCode": -32},
+				}})
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":  cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}})
+		},
+	)
+}
+
+func TestNetworkMixin_TestEmailAndFtp(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	registerMockDeliveryTest()
+
+	smtpHost, smtpPort, stopSmtp := startDeliveryStandIn(t, "220 smtp stand-in ESMTP")
+	defer stopSmtp()
+
+	ftpHost, ftpPort, stopFtp := startDeliveryStandIn(t, "220 ftp stand-in ready")
+
+	result, err := camera.TestEmail(options.WithEmailOptionServer(smtpHost, smtpPort))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Success {
+		t.Errorf("expected the proposed email configuration to work, got %+v", result)
+	}
+
+	// nothing listens on port 1, the camera reports the delivery failure
+	result, err = camera.TestEmail(options.WithEmailOptionServer(smtpHost, 1))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Success || result.RspCode != -32 || result.Detail != "test failed" {
+		t.Errorf("expected the camera's failure detail, got %+v", result)
+	}
+
+	proposed := &models.NetworkFTP{Server: ftpHost, Port: ftpPort, Anonymous: enum.Enabled}
+
+	result, err = camera.TestFtp(options.WithFtpOptionBase(proposed))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Success {
+		t.Errorf("expected the proposed ftp configuration to work, got %+v", result)
+	}
+
+	stopFtp()
+
+	result, err = camera.TestFtp(options.WithFtpOptionBase(proposed))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Success {
+		t.Errorf("expected the ftp test to fail once the server is gone, got %+v", result)
+	}
+}
+
+// a camera at host which moves to the static address it is given, the new address only answers when reachable
+func registerMockLocalLink(host string, link *models.NetworkGeneral, reachable bool) {
+	handle := func(answerHost string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			switch reqData[0].Cmd {
+			case "GetLocalLink":
+				if answerHost != link.Static.Ip {
+					return nil, fmt.Errorf("connection refused")
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetLocalLink",
+					"code":  0,
+					"value": map[string]interface{}{"LocalLink": link},
+				}})
+			case "SetLocalLink":
+				if err := json.Unmarshal(reqData[0].Param["LocalLink"], link); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}})
+		}
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://%s/cgi-bin/api.cgi", host), handle(host))
+
+	if reachable {
+		httpmock.RegisterResponder("POST", "http://127.0.0.2/cgi-bin/api.cgi", handle("127.0.0.2"))
+	}
+}
+
+func TestNetworkMixin_SetLocalLink(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	link := &models.NetworkGeneral{
+		ActiveLink: "LAN",
+		Dns:        models.NetworkGeneralDns{Auto: 1},
+		Static:     models.NetworkGeneralStatic{Gateway: "127.0.0.254", Ip: "127.0.0.1", Mask: "255.255.255.0"},
+		Type:       "DHCP",
+	}
+
+	registerMockLocalLink("127.0.0.1", link, true)
+
+	_, err = camera.SetLocalLink(options.WithLocalLinkOptionStatic("127.0.0.2", "255.255.255.0", "10.0.0.1"))(
+		camera.RestHandler)
+
+	if err == nil {
+		t.Error("expected a gateway outside the network to be rejected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	verified, err := camera.SetLocalLinkVerified(ctx, time.Millisecond,
+		options.WithLocalLinkOptionStatic("127.0.0.2", "255.255.255.0", "127.0.0.254"),
+		options.WithLocalLinkOptionDns("1.1.1.1", ""))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verified.Type != "Static" || verified.Static.Ip != "127.0.0.2" || verified.Dns.Dns1 != "1.1.1.1" {
+		t.Errorf("unexpected link read back %+v", verified)
+	}
+
+	if camera.RestHandler.GetHost() != "127.0.0.2" {
+		t.Errorf("expected the handler to follow the camera, got %s", camera.RestHandler.GetHost())
+	}
+
+	_, err = camera.SetLocalLinkVerified(ctx, time.Millisecond, options.WithLocalLinkOptionDhcp())(camera.RestHandler)
+
+	if err == nil {
+		t.Error("expected switching the handler's static address to DHCP to be refused")
+	}
+}
+
+func TestNetworkMixin_SetLocalLinkUnreachable(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	link := &models.NetworkGeneral{
+		Dns:    models.NetworkGeneralDns{Auto: 1},
+		Static: models.NetworkGeneralStatic{Gateway: "127.0.0.254", Ip: "127.0.0.1", Mask: "255.255.255.0"},
+		Type:   "Static",
+	}
+
+	registerMockLocalLink("127.0.0.1", link, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = camera.SetLocalLinkVerified(ctx, 5*time.Millisecond,
+		options.WithLocalLinkOptionStatic("127.0.0.2", "255.255.255.0", "127.0.0.254"))(camera.RestHandler)
+
+	if err == nil {
+		t.Fatal("expected an unreachable camera to be reported")
+	}
+
+	if camera.RestHandler.GetHost() != "127.0.0.1" {
+		t.Errorf("expected the handler to point at the old address again, got %s", camera.RestHandler.GetHost())
+	}
+
+	t.Log(err)
+}
+
+// a camera which reports the network it was given as connected after joinPolls signal requests
+func registerMockJoinWifi(joinPolls int) {
+	ssid := ""
+	polls := 0
+
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
 
-	networkGeneral, err := camera.GetNetworkStatus()(camera.RestHandler)
+			var value map[string]interface{}
+
+			switch reqData[0].Cmd {
+			case "SetWifi":
+				var wifi models.Wifi
+				_ = json.Unmarshal(reqData[0].Param["Wifi"], &wifi)
+				ssid = wifi.Ssid
+				value = map[string]interface{}{"rspCode": 200}
+			case "GetWifi":
+				value = map[string]interface{}{"Wifi": models.Wifi{Ssid: ssid}}
+			case "GetWifiSignal":
+				polls++
+				signal := 0
+				if polls >= joinPolls {
+					signal = 74
+				}
+				value = map[string]interface{}{"wifiSignal": signal}
+			case "GetLocalLink":
+				value = map[string]interface{}{"LocalLink": models.NetworkGeneral{
+					ActiveLink: "WiFi",
+					Static:     models.NetworkGeneralStatic{Ip: "192.168.1.40"},
+					Type:       "DHCP",
+				}}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   reqData[0].Cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func TestNetworkMixin_JoinWifi(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
 
 	if err != nil {
 		t.Error(err)
 	}
 
-	t.Logf("GetNetworkStatus %v", networkGeneral)
+	registerMockJoinWifi(3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	join, err := camera.JoinWifi(ctx, "home", "secret", time.Millisecond)(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !join.Joined || join.Signal != 74 || join.Ip != "192.168.1.40" || join.ActiveLink != "WiFi" {
+		t.Errorf("unexpected join result %+v", join)
+	}
+
+	signal, err := camera.GetWifiSignal()(camera.RestHandler)
+
+	if err != nil || signal != 74 {
+		t.Errorf("expected signal 74, got %d %v", signal, err)
+	}
+
+	registerMockJoinWifi(1000)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	join, err = camera.JoinWifi(ctx, "home", "wrong", time.Millisecond)(camera.RestHandler)
+
+	if err == nil || join == nil || join.Joined || join.Ssid != "home" {
+		t.Errorf("expected the join to be reported as unconfirmed, got %+v %v", join, err)
+	}
+}
+
+// a camera keeping its upnp and p2p settings, sent holds the settings of the last Set
+func registerMockUpnpP2p(upnp *models.NetworkUpnp, p2p *models.NetworkP2p, sent map[string]json.RawMessage) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			var value map[string]interface{}
+
+			switch reqData[0].Cmd {
+			case "GetUpnp":
+				value = map[string]interface{}{"Upnp": upnp}
+			case "GetP2p":
+				value = map[string]interface{}{"P2p": p2p}
+			case "SetUpnp":
+				sent["Upnp"] = reqData[0].Param["Upnp"]
+				_ = json.Unmarshal(reqData[0].Param["Upnp"], upnp)
+				value = map[string]interface{}{"rspCode": 200}
+			case "SetP2p":
+				sent["P2p"] = reqData[0].Param["P2p"]
+				_ = json.Unmarshal(reqData[0].Param["P2p"], p2p)
+				value = map[string]interface{}{"rspCode": 200}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   reqData[0].Cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func TestNetworkMixin_UpnpP2p(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	sent := map[string]json.RawMessage{}
+	registerMockUpnpP2p(&models.NetworkUpnp{Enable: enum.Enabled},
+		&models.NetworkP2p{Enable: enum.Enabled, Uid: "95270000ABCDEFGH"}, sent)
+
+	p2p, err := camera.GetP2p()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p2p.Enable != enum.Enabled || p2p.Uid != "95270000ABCDEFGH" {
+		t.Errorf("unexpected p2p settings %+v", p2p)
+	}
+
+	if _, err := camera.SetUpnp(enum.Disabled)(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := camera.SetP2p(enum.Disabled)(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	upnp, err := camera.GetUpnp()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p2p, err = camera.GetP2p()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upnp.Enable != enum.Disabled || p2p.Enable != enum.Disabled || p2p.Uid != "95270000ABCDEFGH" {
+		t.Errorf("expected upnp and p2p to be disabled with the uid kept, got %+v %+v", upnp, p2p)
+	}
+
+	if string(sent["Upnp"]) != `{"enable":0}` {
+		t.Errorf("unexpected upnp settings sent %s", sent["Upnp"])
+	}
+}
+
+func TestNetworkOptions_NilBase(t *testing.T) {
+	email := &models.NetworkEmail{SmtpServer: "smtp.example.com", SmtpPort: 465}
+	options.WithEmailOptionBase(nil)(email)
+
+	if email.SmtpServer != "smtp.example.com" || email.SmtpPort != 465 {
+		t.Errorf("a nil email base should be ignored, got %+v", email)
+	}
+
+	ftp := &models.NetworkFTP{Server: "ftp.example.com", Port: 21}
+	options.WithFtpOptionBase(nil)(ftp)
+
+	if ftp.Server != "ftp.example.com" || ftp.Port != 21 {
+		t.Errorf("a nil ftp base should be ignored, got %+v", ftp)
+	}
+
+	ports := &models.NetworkPort{HttpPort: 80, RtspPort: 554}
+	options.WithNetworkPortOptionBase(nil)(ports)
+
+	if ports.HttpPort != 80 || ports.RtspPort != 554 {
+		t.Errorf("a nil port base should be ignored, got %+v", ports)
+	}
+
+	ntp := &models.NetworkNTP{Server: "pool.ntp.org"}
+	options.WithNtpOptionBase(nil)(ntp)
+
+	if ntp.Server != "pool.ntp.org" {
+		t.Errorf("a nil ntp base should be ignored, got %+v", ntp)
+	}
+
+	ddns := &models.NetworkDDNS{Domain: "camera.example.com"}
+	options.WithDdnsOptionBase(nil)(ddns)
+
+	if ddns.Domain != "camera.example.com" {
+		t.Errorf("a nil ddns base should be ignored, got %+v", ddns)
+	}
+
+	push := &models.NetworkPush{Schedule: models.Schedule{Enable: enum.Enabled}}
+	options.WithPushOptionBase(nil)(push)
+
+	if push.Schedule.Enable != enum.Enabled {
+		t.Errorf("a nil push base should be ignored, got %+v", push)
+	}
 }
@@ -2,14 +2,20 @@ package test
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 func registerMockGetPreset() {
@@ -241,7 +247,7 @@ func registerMockPtzOperation() {
 	)
 }
 
-func TestPtzMixin_GetPreset(t *testing.T)  {
+func TestPtzMixin_GetPreset(t *testing.T) {
 	httpmock.Activate()
 
 	defer httpmock.DeactivateAndReset()
@@ -265,8 +271,8 @@ func TestPtzMixin_GetPreset(t *testing.T)  {
 		t.Error(err)
 	}
 
-	if len(preset) != 1 || preset["pos1"] != 1 {
-		t.Errorf("Get unexpected presets")
+	if len(preset) != 2 || preset[0].Name != "pos1" || preset[0].Enable != 1 || preset[1].Enable != 0 {
+		t.Errorf("Get unexpected presets %v", preset)
 	}
 
 	t.Log("GetPreset successfully")
@@ -354,6 +360,266 @@ func TestPtzMixin_RemovePreset(t *testing.T) {
 
 }
 
+// a camera keeping its presets in memory, SetPtzPreset stores and deletes them and every command is recorded
+func registerMockPresetStore(presets map[int]*models.PtzPreset, commands *[]string) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			setOk := map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}
+
+			switch reqData[0].Cmd {
+			case "GetPtzPreset":
+				var list []*models.PtzPreset
+				for i := 1; i <= 4; i++ {
+					if p, ok := presets[i]; ok {
+						list = append(list, p)
+					} else {
+						list = append(list, &models.PtzPreset{Index: i, Name: fmt.Sprintf("pos%d", i)})
+					}
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzPreset",
+					"code":  0,
+					"value": map[string]interface{}{"PtzPreset": list},
+				}})
+			case "SetPtzPreset":
+				var preset models.PtzPreset
+
+				if err := json.Unmarshal(reqData[0].Param["PtzPreset"], &preset); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				if preset.Enable == 1 {
+					presets[preset.Index] = &preset
+					*commands = append(*commands, fmt.Sprintf("save %d %s", preset.Index, preset.Name))
+				} else {
+					delete(presets, preset.Index)
+					*commands = append(*commands, fmt.Sprintf("delete %d", preset.Index))
+				}
+			case "PtzCtrl":
+				var id int
+				_ = json.Unmarshal(reqData[0].Param["id"], &id)
+				*commands = append(*commands, fmt.Sprintf("goto %d", id))
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{setOk})
+		},
+	)
+}
+
+func TestPtzMixin_SyncPresets(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	presets := map[int]*models.PtzPreset{
+		1: {Enable: 1, Index: 1, Name: "gate"},
+		2: {Enable: 1, Index: 2, Name: "yard"},
+		3: {Enable: 1, Index: 3, Name: "old"},
+	}
+	var commands []string
+
+	registerMockPresetStore(presets, &commands)
+
+	desired := []models.PtzPreset{
+		{Index: 1, Name: "gate"},
+		{Index: 2, Name: "driveway"},
+		{Index: 4, Name: "door"},
+	}
+
+	plan, err := camera.SyncPresets(context.Background(), desired,
+		api.PtzPresetSyncOptionDryRun(true))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Removed) != 0 || len(plan.Added) != 1 {
+		t.Errorf("presets missing from the list should be kept by default:\n%s", plan)
+	}
+
+	plan, err = camera.SyncPresets(context.Background(), desired, api.PtzPresetSyncOptionDryRun(true),
+		api.PtzPresetSyncOptionRemoveUnlisted(true))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commands) != 0 {
+		t.Errorf("dry run changed the camera: %v", commands)
+	}
+
+	if len(plan.Added) != 1 || plan.Added[0].Index != 4 ||
+		len(plan.Renamed) != 1 || plan.Renamed[0].From != "yard" || plan.Renamed[0].To != "driveway" ||
+		len(plan.Removed) != 1 || plan.Removed[0].Index != 3 {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+
+	applied, err := camera.SyncPresets(context.Background(), desired, api.PtzPresetSyncOptionRemoveUnlisted(true),
+		api.PtzPresetSyncOptionSettle(time.Millisecond))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"delete 3", "goto 2", "save 2 driveway", "save 4 door"}
+
+	if fmt.Sprint(commands) != fmt.Sprint(expected) {
+		t.Errorf("expected commands %v, got %v", expected, commands)
+	}
+
+	if applied.String() != plan.String() {
+		t.Errorf("applied diff differs from the plan:\n%s\n%s", applied, plan)
+	}
+
+	again, err := camera.SyncPresets(context.Background(), desired)(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !again.Empty() {
+		t.Errorf("presets should be in sync, got:\n%s", again)
+	}
+
+	_, err = camera.SyncPresets(context.Background(), []models.PtzPreset{
+		{Index: 1, Name: "a"}, {Index: 1, Name: "b"},
+	})(camera.RestHandler)
+
+	if err == nil {
+		t.Error("expected duplicate preset ids to be rejected")
+	}
+
+	t.Logf("SyncPresets successfully:\n%s", applied)
+}
+
+// records the url command and the body of every request
+type bodyRecorder struct {
+	commands []string
+	bodies   []string
+}
+
+func registerMockBodyRecorder(recorder *bodyRecorder) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			recorder.commands = append(recorder.commands, req.URL.Query().Get("cmd"))
+			recorder.bodies = append(recorder.bodies, string(data))
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   req.URL.Query().Get("cmd"),
+				"code":  0,
+				"value": map[string]interface{}{"rspCode": 200},
+			}})
+		},
+	)
+}
+
+func TestPtzMixin_PresetRequestBodies(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &bodyRecorder{}
+	registerMockBodyRecorder(recorder)
+
+	index := 3
+
+	if _, err := camera.GoToPreset(api.PtzOptionOpsIndex(&index), api.PtzOptionOpsSpeed(30))(
+		camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := camera.AddPreset(api.PtzOptionPresetIndex(3), api.PtzOptionsPresetName("door"))(
+		camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := camera.RemovePreset(api.PtzOptionPresetIndex(3), api.PtzOptionsPresetName("door"))(
+		camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		command string
+		body    string
+	}{
+		{"PtzCtrl", `[{"cmd":"PtzCtrl","action":0,"param":{"channel":0,"op":"ToPos","id":3,"speed":30}}]`},
+		{"SetPtzPreset", `[{"cmd":"SetPtzPreset","action":0,` +
+			`"param":{"PtzPreset":{"channel":0,"enable":1,"id":3,"name":"door"}}}]`},
+		{"SetPtzPreset", `[{"cmd":"SetPtzPreset","action":0,` +
+			`"param":{"PtzPreset":{"channel":0,"enable":0,"id":3,"name":"door"}}}]`},
+	}
+
+	if len(recorder.bodies) != len(expected) {
+		t.Fatalf("expected %d requests, got %v", len(expected), recorder.bodies)
+	}
+
+	for i, e := range expected {
+		var got, want interface{}
+
+		if err := json.Unmarshal([]byte(recorder.bodies[i]), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := json.Unmarshal([]byte(e.body), &want); err != nil {
+			t.Fatal(err)
+		}
+
+		if recorder.commands[i] != e.command || !reflect.DeepEqual(got, want) {
+			t.Errorf("request %d: expected %s %s, got %s %s", i, e.command, e.body, recorder.commands[i],
+				recorder.bodies[i])
+		}
+	}
+}
+
 func TestPtzMixin_MoveRight(t *testing.T) {
 	httpmock.Activate()
 
@@ -623,3 +889,770 @@ func TestPtzMixin_AutoMovement(t *testing.T) {
 
 	t.Logf("AutoMovement %v", ok)
 }
+
+func registerMockPtzPatrol() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			setOk := map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}
+
+			switch reqData[0].Cmd {
+			case "GetPtzPreset":
+				presets := []*models.PtzPreset{
+					{Channel: 0, Enable: 1, Index: 1, Name: "gate"},
+					{Channel: 0, Enable: 1, Index: 2, Name: "yard"},
+					{Channel: 0, Enable: 0, Index: 3, Name: "pos3"},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzPreset",
+					"code":  0,
+					"value": map[string]interface{}{"PtzPreset": presets},
+				}})
+			case "GetPtzPatrol":
+				patrols := []*models.PtzPatrol{
+					{
+						Channel: 0,
+						Enable:  1,
+						Index:   0,
+						Running: 0,
+						Preset: []models.PtzPatrolPreset{
+							{DwellTime: 10, Index: 1, Speed: 20},
+							{DwellTime: 15, Index: 2, Speed: 20},
+						},
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzPatrol",
+					"code":  0,
+					"value": map[string]interface{}{"PtzPatrol": patrols},
+				}})
+			case "SetPtzPatrol":
+				var patrol *models.PtzPatrol
+
+				if err := json.Unmarshal(reqData[0].Param["PtzPatrol"], &patrol); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				log.Printf("received PtzPatrol: %+v", patrol)
+
+				return httpmock.NewJsonResponse(200, []interface{}{setOk})
+			case "PtzCtrl":
+				var op string
+				var id int
+
+				if err := json.Unmarshal(reqData[0].Param["op"], &op); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				if err := json.Unmarshal(reqData[0].Param["id"], &id); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				// the patrol is only sent as id, never as index as well
+				if _, ok := reqData[0].Param["index"]; ok {
+					return httpmock.NewStringResponse(500, "patrol sent as index"), nil
+				}
+
+				if (op != "StartPatrol" && op != "StopPatrol") || id != 0 {
+					return httpmock.NewStringResponse(500, "unexpected patrol operation"), nil
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{setOk})
+			}
+
+			return httpmock.NewStringResponse(500, "Operation Unknown"), nil
+		},
+	)
+}
+
+func TestPtzMixin_GetPtzPatrol(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	registerMockPtzPatrol()
+
+	patrols, err := camera.GetPtzPatrol()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(patrols) != 1 || len(patrols[0].Preset) != 2 || patrols[0].Preset[1].DwellTime != 15 {
+		t.Errorf("unexpected patrols %+v", patrols)
+	}
+
+	t.Logf("GetPtzPatrol %+v", patrols)
+}
+
+func TestPtzMixin_SetPtzPatrol(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	registerMockPtzPatrol()
+
+	patrol := &models.PtzPatrol{
+		Enable: 1,
+		Index:  0,
+		Preset: []models.PtzPatrolPreset{
+			{DwellTime: 30, Index: 2, Speed: 10},
+			{DwellTime: 30, Index: 1, Speed: 10},
+		},
+	}
+
+	ok, err := camera.SetPtzPatrol(patrol)(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Logf("SetPtzPatrol %v", ok)
+
+	// preset 3 is disabled so it can not be patrolled
+	patrol.Preset = append(patrol.Preset, models.PtzPatrolPreset{DwellTime: 30, Index: 3, Speed: 10})
+
+	ok, err = camera.SetPtzPatrol(patrol)(camera.RestHandler)
+
+	if err == nil {
+		t.Errorf("expected an error for a patrol with an unknown preset")
+	}
+
+	t.Logf("SetPtzPatrol %v %v", ok, err)
+}
+
+func TestPtzMixin_StartStopPatrol(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	registerMockPtzPatrol()
+
+	ok, err := camera.StartPatrol(0)(camera.RestHandler)
+
+	if err != nil || !ok {
+		t.Errorf("StartPatrol %v %v", ok, err)
+	}
+
+	t.Logf("StartPatrol %v", ok)
+
+	ok, err = camera.StopPatrol(0)(camera.RestHandler)
+
+	if err != nil || !ok {
+		t.Errorf("StopPatrol %v %v", ok, err)
+	}
+
+	t.Logf("StopPatrol %v", ok)
+}
+
+// records every PtzCtrl operation received, moves are rejected when failMove is true
+type ptzRecorder struct {
+	mu       sync.Mutex
+	ops      []string
+	failMove bool
+}
+
+func (r *ptzRecorder) operations() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.ops...)
+}
+
+func registerMockPtzRecorder(recorder *ptzRecorder) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			var op string
+
+			if err := json.Unmarshal(reqData[0].Param["op"], &op); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			recorder.mu.Lock()
+			recorder.ops = append(recorder.ops, op)
+			failMove := recorder.failMove
+			recorder.mu.Unlock()
+
+			if failMove && op != "Stop" {
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "PtzCtrl",
+					"code":  1,
+					"error": map[string]interface{}{"detail": "ptz busy", "rspCode": -1},
+				}})
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":  "PtzCtrl",
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}})
+		},
+	)
+}
+
+func TestPtzMixin_MoveFor(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	ok, err := camera.MoveFor(context.Background(), enum.PTZ_RIGHT, 20, 10*time.Millisecond)(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if ops := recorder.operations(); len(ops) != 2 || ops[0] != "Right" || ops[1] != "Stop" {
+		t.Errorf("expected Right then Stop, got %v", ops)
+	}
+
+	t.Logf("MoveFor %v", ok)
+}
+
+func TestPtzMixin_MoveForCancelled(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = camera.MoveFor(ctx, enum.PTZ_LEFT_UP, 20, time.Minute)(camera.RestHandler)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("MoveFor did not return on cancellation")
+	}
+
+	if ops := recorder.operations(); len(ops) != 2 || ops[1] != "Stop" {
+		t.Errorf("expected a Stop after cancellation, got %v", ops)
+	}
+
+	// a failed start must still be followed by a stop
+	recorder.mu.Lock()
+	recorder.failMove = true
+	recorder.mu.Unlock()
+
+	_, err = camera.MoveFor(context.Background(), enum.PTZ_DOWN, 20, time.Minute)(camera.RestHandler)
+
+	if err == nil {
+		t.Errorf("expected the failed move to be reported")
+	}
+
+	if ops := recorder.operations(); len(ops) != 4 || ops[2] != "Down" || ops[3] != "Stop" {
+		t.Errorf("expected Down then Stop, got %v", ops)
+	}
+}
+
+func TestPtzMixin_MoveSteps(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	ok, err := camera.MoveSteps(context.Background(), enum.PTZ_UP, 10, 3, time.Millisecond)(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []string{"Up", "Stop", "Up", "Stop", "Up", "Stop"}
+
+	if ops := recorder.operations(); len(ops) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, ops)
+	} else {
+		for i := range expected {
+			if ops[i] != expected[i] {
+				t.Errorf("expected %v, got %v", expected, ops)
+				break
+			}
+		}
+	}
+
+	t.Logf("MoveSteps %v", ok)
+}
+
+func TestPtzMixin_PtzWatchdog(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	recorder := &ptzRecorder{}
+	registerMockPtzRecorder(recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watchdog := camera.StartPtzWatchdog(ctx, 20*time.Millisecond)(camera.RestHandler)
+
+	// nothing is stopped before the first keep-alive
+	time.Sleep(50 * time.Millisecond)
+
+	if ops := recorder.operations(); len(ops) != 0 {
+		t.Errorf("expected no operations before a keep-alive, got %v", ops)
+	}
+
+	watchdog.KeepAlive()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if ops := recorder.operations(); len(ops) != 1 || ops[0] != "Stop" {
+		t.Errorf("expected a single Stop after the keep-alive expired, got %v", ops)
+	}
+
+	cancel()
+	<-watchdog.Done()
+
+	if ops := recorder.operations(); len(ops) != 2 || ops[1] != "Stop" {
+		t.Errorf("expected a final Stop on cancellation, got %v", ops)
+	}
+}
+
+// a camera keeping its guard settings in memory, every guard command received is recorded
+func registerMockPtzGuard(guard *models.PtzGuard, commands *[]string) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			setOk := map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}
+
+			switch reqData[0].Cmd {
+			case "GetPtzGuard":
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzGuard",
+					"code":  0,
+					"value": map[string]interface{}{"PtzGuard": guard},
+				}})
+			case "SetPtzGuard":
+				type ptzGuard struct {
+					CmdStr         string `json:"cmdStr"`
+					Enable         *int   `json:"benable"`
+					Timeout        *int   `json:"timeout"`
+					SaveCurrentPos int    `json:"bSaveCurrentPos"`
+				}
+
+				var received ptzGuard
+
+				if err := json.Unmarshal(reqData[0].Param["PtzGuard"], &received); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				if received.CmdStr == "setPos" {
+					guard.Enable = *received.Enable
+					guard.Timeout = *received.Timeout
+
+					if received.SaveCurrentPos == 1 {
+						guard.ExistPos = 1
+					}
+				}
+
+				*commands = append(*commands, received.CmdStr)
+
+				return httpmock.NewJsonResponse(200, []interface{}{setOk})
+			case "PtzCtrl":
+				var op string
+
+				if err := json.Unmarshal(reqData[0].Param["op"], &op); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				*commands = append(*commands, op)
+
+				return httpmock.NewJsonResponse(200, []interface{}{setOk})
+			}
+
+			return httpmock.NewStringResponse(500, "Operation Unknown"), nil
+		},
+	)
+}
+
+func TestPtzMixin_PtzGuard(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	guard := &models.PtzGuard{Channel: 0, Enable: 0, ExistPos: 0, Timeout: 60}
+	var commands []string
+
+	registerMockPtzGuard(guard, &commands)
+
+	current, err := camera.GetPtzGuard()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("GetPtzGuard %+v", current)
+
+	// enabling without any stored position is refused
+	_, err = camera.SetPtzGuard(api.PtzGuardOptionEnable(true))(camera.RestHandler)
+
+	if err == nil {
+		t.Errorf("expected enabling the guard without a position to fail")
+	}
+
+	guardOptions := make([]api.OptionPtzGuard, 1, 2)
+	guardOptions[0] = api.PtzGuardOptionEnable(true)
+
+	ok, err := camera.SetPtzGuardFromPreset(context.Background(), 2, time.Millisecond,
+		guardOptions...)(camera.RestHandler)
+
+	if guardOptions[:2][1] != nil {
+		t.Error("SetPtzGuardFromPreset wrote into the spare capacity of the options")
+	}
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if guard.Enable != 1 || guard.ExistPos != 1 || guard.Timeout != 60 {
+		t.Errorf("unexpected guard %+v", guard)
+	}
+
+	t.Logf("SetPtzGuardFromPreset %v", ok)
+
+	ok, err = camera.SetPtzGuard(api.PtzGuardOptionTimeout(300))(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if guard.Enable != 1 || guard.Timeout != 300 {
+		t.Errorf("unexpected guard %+v", guard)
+	}
+
+	ok, err = camera.GoToGuard()(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []string{"ToPos", "setPos", "setPos", "toPos"}
+
+	if len(commands) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, commands)
+	}
+
+	for i := range expected {
+		if commands[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, commands)
+			break
+		}
+	}
+
+	t.Logf("GoToGuard %v", ok)
+}
+
+// a camera whose self-check finishes after checkPolls state requests and which keeps its ptz serial settings
+func registerMockPtzCheck(b485 int, checkPolls int, serial *models.PtzSerial) {
+	polls := 0
+
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			setOk := map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}
+
+			switch reqData[0].Cmd {
+			case "PtzCheck":
+				polls = 0
+			case "GetPtzCheckState":
+				polls++
+				state := 1
+				if polls >= checkPolls {
+					state = 2
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzCheckState",
+					"code":  0,
+					"value": map[string]interface{}{"PtzCheckState": state},
+				}})
+			case "GetDevInfo":
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetDevInfo",
+					"code":  0,
+					"value": map[string]interface{}{"DevInfo": models.DeviceInformation{B485: b485}},
+				}})
+			case "GetPtzSerial":
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetPtzSerial",
+					"code":  0,
+					"value": map[string]interface{}{"PtzSerial": serial},
+				}})
+			case "SetPtzSerial":
+				if err := json.Unmarshal(reqData[0].Param["PtzSerial"], serial); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{setOk})
+		},
+	)
+}
+
+func TestPtzMixin_PtzCheck(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	registerMockPtzCheck(0, 3, nil)
+
+	if _, err := camera.PtzCheck()(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := camera.GetPtzCheckState()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state != enum.PTZ_CHECK_RUNNING {
+		t.Errorf("expected the check to be running, got %s", state)
+	}
+
+	ok, err := camera.WaitForPtzCheck(context.Background(), time.Millisecond)(camera.RestHandler)
+
+	if err != nil || !ok {
+		t.Fatalf("expected the check to finish, got %v %v", ok, err)
+	}
+
+	registerMockPtzCheck(0, 1000, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := camera.WaitForPtzCheck(ctx, time.Millisecond)(camera.RestHandler); err == nil {
+		t.Error("expected waiting to stop when the context is done")
+	}
+}
+
+func TestPtzMixin_PtzSerial(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	serial := &models.PtzSerial{
+		BaudRate:     1200,
+		CtrlAddr:     1,
+		CtrlProtocol: "PELCO_D",
+		DataBit:      "CS8",
+		FlowCtrl:     "none",
+		Parity:       "none",
+		StopBit:      1,
+	}
+
+	registerMockPtzCheck(0, 1, serial)
+
+	if _, err := camera.GetPtzSerial()(camera.RestHandler); err == nil {
+		t.Error("expected cameras without RS485 to be rejected")
+	}
+
+	registerMockPtzCheck(1, 1, serial)
+
+	_, err = camera.SetPtzSerial(api.PtzSerialOptionBaudRate(9600), api.PtzSerialOptionAddress(3))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := camera.GetPtzSerial()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.BaudRate != 9600 || got.CtrlAddr != 3 || got.CtrlProtocol != "PELCO_D" {
+		t.Errorf("unexpected ptz serial settings %v", got)
+	}
+}
@@ -0,0 +1,235 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rebootingCamera drops off the network for a number of requests once it is told to reboot, forever when down is
+// negative. Logins take loginDelay to answer, inFlight counts the requests not answered yet.
+type rebootingCamera struct {
+	mu sync.Mutex
+
+	down       int
+	rebooted   bool
+	rtspEnable int
+	rtspPort   int
+	loginDelay time.Duration
+	inFlight   int
+	commands   []string
+}
+
+func registerMockRebootingCamera(rc *rebootingCamera) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+			rc.mu.Lock()
+			defer rc.mu.Unlock()
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if err := json.Unmarshal(data, &reqData); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+			rc.commands = append(rc.commands, cmd)
+
+			if cmd == "Login" && rc.loginDelay > 0 {
+				rc.inFlight++
+				rc.mu.Unlock()
+				time.Sleep(rc.loginDelay)
+				rc.mu.Lock()
+				rc.inFlight--
+			}
+
+			if rc.rebooted && rc.down != 0 {
+				if rc.down > 0 {
+					rc.down--
+				}
+
+				return nil, fmt.Errorf("connection refused")
+			}
+
+			var value map[string]interface{}
+
+			switch cmd {
+			case "Reboot":
+				rc.rebooted = true
+				value = map[string]interface{}{"rspCode": 200}
+			case "Login":
+				value = map[string]interface{}{
+					"Token": map[string]interface{}{"Name": "12345", "LeaseTime": 3600},
+				}
+			case "GetDevInfo":
+				value = map[string]interface{}{"DevInfo": map[string]interface{}{"model": "RLC-410"}}
+			case "GetNetPort":
+				value = map[string]interface{}{
+					"NetPort": map[string]interface{}{"rtspEnable": rc.rtspEnable, "rtspPort": rc.rtspPort},
+				}
+			default:
+				value = map[string]interface{}{"rspCode": 200}
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func newRebootingCamera(t *testing.T, rc *rebootingCamera) *reolinkapi.Camera {
+	registerMockAuth()
+
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerMockRebootingCamera(rc)
+
+	return camera
+}
+
+func TestCamera_RebootAndWait(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	rtsp, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer rtsp.Close()
+
+	rc := &rebootingCamera{
+		down:       3,
+		rtspEnable: 1,
+		rtspPort:   rtsp.Addr().(*net.TCPAddr).Port,
+	}
+
+	camera := newRebootingCamera(t, rc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = camera.RebootAndWait(ctx, reolinkapi.RebootOptionPollInterval(time.Millisecond),
+		reolinkapi.RebootOptionCheckRtsp())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.commands[0] != "GetNetPort" || rc.commands[1] != "Reboot" {
+		t.Errorf("expected the RTSP port to be read before rebooting, got %v", rc.commands)
+	}
+
+	if last := rc.commands[len(rc.commands)-1]; last != "Login" || rc.down != 0 {
+		t.Errorf("expected a login once the camera is back, got %v", rc.commands)
+	}
+}
+
+func TestCamera_RebootAndWaitDeadline(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	rc := &rebootingCamera{down: -1}
+
+	camera := newRebootingCamera(t, rc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := camera.RebootAndWait(ctx, reolinkapi.RebootOptionPollInterval(time.Millisecond))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	t.Logf("RebootAndWait %v", err)
+}
+
+func TestCamera_RebootAndWaitSlowLogin(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	rc := &rebootingCamera{}
+
+	camera := newRebootingCamera(t, rc)
+
+	rc.mu.Lock()
+	rc.down = 1
+	rc.loginDelay = 100 * time.Millisecond
+	rc.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	err := camera.RebootAndWait(ctx, reolinkapi.RebootOptionPollInterval(time.Millisecond))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	// a login answered after RebootAndWait returned would replace the token behind the caller's back
+	if rc.inFlight != 0 {
+		t.Errorf("RebootAndWait returned with %d login still in flight", rc.inFlight)
+	}
+
+	t.Logf("RebootAndWait %v", err)
+}
+
+func TestCamera_RebootAndWaitRtspDisabled(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	rc := &rebootingCamera{}
+
+	camera := newRebootingCamera(t, rc)
+
+	err := camera.RebootAndWait(context.Background(), reolinkapi.RebootOptionCheckRtsp())
+
+	if err == nil {
+		t.Fatal("expected RebootAndWait to refuse a camera with RTSP disabled")
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, cmd := range rc.commands {
+		if cmd == "Reboot" {
+			t.Errorf("camera was rebooted although its RTSP port cannot be checked")
+		}
+	}
+}
@@ -39,7 +39,7 @@ func registerMockGetRecordingEncoding() {
 			}
 
 			encoding := &models.Encoding{
-				Audio:   false,
+				Audio:   enum.Disabled,
 				Channel: 0,
 				MainStream: models.RecordingMainStream{
 					BitRate:   enum.MAIN_BIT_RATE_1024.Value(),
@@ -95,11 +95,11 @@ func registerMockGetRecordingAdvanced() {
 
 			recording := &models.Recording{
 				Channel:    0,
-				Overwrite:  true,
+				Overwrite:  enum.Enabled,
 				PostRecord: enum.POST_RECORD_SECONDS_30.Value(),
-				PreRecord:  true,
+				PreRecord:  enum.Enabled,
 				Schedule: models.Schedule{
-					Enable: true,
+					Enable: enum.Enabled,
 					Table:  "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
 				},
 			}
@@ -142,6 +142,33 @@ func registerMockSetRecordingEncoding() {
 				return httpmock.NewStringResponse(500, err.Error()), nil
 			}
 
+			if reqData[0].Cmd == "GetEnc" {
+				generalData := map[string]interface{}{
+					"cmd":  "GetEnc",
+					"code": 0,
+					"value": map[string]interface{}{
+						"Enc": &models.Encoding{
+							Audio:   enum.Enabled,
+							Channel: 0,
+							MainStream: models.RecordingMainStream{
+								BitRate:   enum.MAIN_BIT_RATE_4096.Value(),
+								FrameRate: enum.MAIN_FRAME_RATE_15.Value(),
+								Profile:   "High",
+								Size:      enum.MAIN_SIZE_3072_1728.Value(),
+							},
+							SubStream: models.RecordingSubStream{
+								BitRate:   enum.SUB_BIT_RATE_160.Value(),
+								FrameRate: enum.SUB_FRAME_RATE_7.Value(),
+								Profile:   "High",
+								Size:      enum.SUB_SIZE_640_360.Value(),
+							},
+						},
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
 			var encoding *models.Encoding
 
 			err = json.Unmarshal(reqData[0].Param["Enc"], &encoding)
@@ -248,3 +275,133 @@ func TestRecordingMixin_SetRecordingEncoding(t *testing.T) {
 
 	t.Logf("SetRecordingEncoding %v", recordingInfo)
 }
+
+func registerMockRecordingEncodingSettings() {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if reqData[0].Cmd == "GetEnc" {
+				// answer with a real camera response including its initial and range blocks
+				response, err := ioutil.ReadFile("../examples/response/GetEnc.json")
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				return httpmock.NewBytesResponse(200, response), nil
+			}
+
+			if reqData[0].Cmd == "SetEnc" {
+				generalData := map[string]interface{}{
+					"cmd":  "SetEnc",
+					"code": 0,
+					"value": map[string]interface{}{
+						"rspCode": 200,
+					},
+				}
+
+				return httpmock.NewJsonResponse(200, []interface{}{generalData})
+			}
+
+			return httpmock.NewStringResponse(500, "Operation Unknown"), nil
+		},
+	)
+}
+
+func TestRecordingMixin_GetRecordingEncodingSettings(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockRecordingEncodingSettings()
+
+	encodingSettings, err := camera.GetRecordingEncodingSettings()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encodingSettings.Initial == nil || encodingSettings.Initial.MainStream.BitRate != 4096 {
+		t.Errorf("unexpected initial encoding %+v", encodingSettings.Initial)
+	}
+
+	if len(encodingSettings.Range) != 5 {
+		t.Errorf("expected a range for 5 main stream sizes, got %d", len(encodingSettings.Range))
+	}
+
+	t.Logf("GetRecordingEncodingSettings %+v", encodingSettings)
+}
+
+func TestRecordingMixin_SetRecordingEncodingOutOfRange(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if camera.GetToken() == "12345" {
+		t.Logf("login successful")
+	}
+
+	registerMockRecordingEncodingSettings()
+
+	// 640*480 is not a sub stream size the camera offers
+	ok, err := camera.SetRecordingEncoding(
+		func(encoding *models.Encoding) {
+			encoding.SubStream.Size = "640*480"
+		},
+	)(camera.RestHandler)
+
+	if _, isValidationError := err.(*models.ValidationError); !isValidationError {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+
+	t.Logf("SetRecordingEncoding %v %v", ok, err)
+
+	ok, err = camera.SetRecordingEncoding(
+		api.RecordingEncodingOptionMainSize(enum.MAIN_SIZE_2560_1440),
+		api.RecordingEncodingOptionMainFrameRate(enum.MAIN_FRAME_RATE_20),
+	)(camera.RestHandler)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Logf("SetRecordingEncoding %v", ok)
+}
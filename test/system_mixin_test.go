@@ -3,6 +3,7 @@ package test
 import (
 	"encoding/json"
 	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
 	"io/ioutil"
@@ -37,7 +38,7 @@ func registerMockGetGeneralSystem() {
 
 			if reqData[0].Cmd == "GetTime" {
 				systemDst := &models.DstInformation{
-					Enable:       true,
+					Enable:       enum.Enabled,
 					EndHour:      1,
 					EndMin:       0,
 					EndMon:       11,
@@ -56,7 +57,7 @@ func registerMockGetGeneralSystem() {
 				systemTime := &models.TimeInformation{
 					Day:      1,
 					Hour:     15,
-					HourFmt:  false,
+					HourFmt:  0,
 					Min:      33,
 					Mon:      12,
 					Sec:      58,
@@ -0,0 +1,329 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/api"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/enum"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/options"
+	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
+	"github.com/jarcoal/httpmock"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		t.Skipf("time zone %s is not available: %v", name, err)
+	}
+
+	return loc
+}
+
+func TestModels_DstFromLocation(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected models.DstInformation
+	}{
+		{
+			// second Sunday of March to first Sunday of November
+			zone: "America/Chicago",
+			expected: models.DstInformation{Enable: enum.Enabled, Offset: 1,
+				StartMon: 3, StartWeek: 2, StartWeekday: 0, StartHour: 2,
+				EndMon: 11, EndWeek: 1, EndWeekday: 0, EndHour: 2},
+		},
+		{
+			// last Sunday of March to last Sunday of October
+			zone: "Europe/Berlin",
+			expected: models.DstInformation{Enable: enum.Enabled, Offset: 1,
+				StartMon: 3, StartWeek: 5, StartWeekday: 0, StartHour: 2,
+				EndMon: 10, EndWeek: 5, EndWeekday: 0, EndHour: 3},
+		},
+		{
+			// first Sunday of October to first Sunday of April
+			zone: "Australia/Sydney",
+			expected: models.DstInformation{Enable: enum.Enabled, Offset: 1,
+				StartMon: 10, StartWeek: 1, StartWeekday: 0, StartHour: 2,
+				EndMon: 4, EndWeek: 1, EndWeekday: 0, EndHour: 3},
+		},
+		{
+			zone:     "Asia/Tokyo",
+			expected: models.DstInformation{Enable: enum.Disabled},
+		},
+	}
+
+	for _, test := range tests {
+		dst, err := models.DstFromLocation(loadLocation(t, test.zone), 2021)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.zone, err)
+			continue
+		}
+
+		if *dst != test.expected {
+			t.Errorf("%s: got %+v, expected %+v", test.zone, *dst, test.expected)
+		}
+	}
+
+	// half an hour of daylight saving cannot be expressed
+	if _, err := models.DstFromLocation(loadLocation(t, "Australia/Lord_Howe"), 2021); err == nil {
+		t.Error("Australia/Lord_Howe should be refused")
+	}
+}
+
+func TestModels_TimeInformation(t *testing.T) {
+	chicago := loadLocation(t, "America/Chicago")
+
+	dst, err := models.DstFromLocation(chicago, 2020)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// see examples/response/GetDSTInfo.json
+	timeInformation := &models.TimeInformation{Year: 2020, Mon: 10, Day: 27, Hour: 18, Min: 50, Sec: 46,
+		TimeZone: 21600}
+
+	if _, offset := time.Now().In(timeInformation.Location()).Zone(); offset != -21600 {
+		t.Errorf("expected the camera's zone to be UTC-6, got %d", offset)
+	}
+
+	expected := time.Date(2020, time.October, 27, 18, 50, 46, 0, chicago)
+
+	if cameraTime := timeInformation.Time(dst); !cameraTime.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, cameraTime)
+	}
+
+	winter := &models.TimeInformation{Year: 2020, Mon: 12, Day: 1, Hour: 8, TimeZone: 21600}
+	expected = time.Date(2020, time.December, 1, 8, 0, 0, 0, chicago)
+
+	if cameraTime := winter.Time(dst); !cameraTime.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, cameraTime)
+	}
+
+	summer := &models.TimeInformation{TimeFmt: "MM/DD/YYYY"}
+	summer.SetTime(time.Date(2021, time.July, 4, 12, 30, 0, 0, chicago))
+
+	expectedInformation := models.TimeInformation{Year: 2021, Mon: 7, Day: 4, Hour: 12, Min: 30,
+		TimeFmt: "MM/DD/YYYY", TimeZone: 21600}
+
+	if *summer != expectedInformation {
+		t.Errorf("SetTime got %+v, expected %+v", *summer, expectedInformation)
+	}
+}
+
+// clockCamera runs its clock ahead of the host by drift and records what SetTime sends
+type clockCamera struct {
+	location *time.Location
+	drift    time.Duration
+	set      map[string]json.RawMessage
+}
+
+func registerMockClockCamera(cc *clockCamera) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			if err := json.Unmarshal(data, &reqData); err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			cmd := reqData[0].Cmd
+			value := map[string]interface{}{"rspCode": 200}
+
+			switch cmd {
+			case "GetTime":
+				now := time.Now().Add(cc.drift).In(cc.location)
+
+				dst, err := models.DstFromLocation(cc.location, now.Year())
+
+				if err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				timeInformation := &models.TimeInformation{TimeFmt: "MM/DD/YYYY", HourFmt: 1}
+				timeInformation.SetTime(now)
+
+				value = map[string]interface{}{"Dst": dst, "Time": timeInformation}
+			case "SetTime":
+				cc.set = reqData[0].Param
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+				"cmd":   cmd,
+				"code":  0,
+				"value": value,
+			}})
+		},
+	)
+}
+
+func TestSystemMixin_SyncTimeFromHost(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	chicago := loadLocation(t, "America/Chicago")
+	berlin := loadLocation(t, "Europe/Berlin")
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc := &clockCamera{location: chicago, drift: 90 * time.Second}
+	registerMockClockCamera(cc)
+
+	drift, err := camera.SyncTimeFromHost(api.SyncTimeOptionLocation(berlin),
+		api.SyncTimeOptionThreshold(5*time.Minute))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if drift < 88*time.Second || drift > 92*time.Second {
+		t.Errorf("expected a drift of 90s, got %v", drift)
+	}
+
+	if cc.set != nil {
+		t.Fatalf("a drift within the threshold should not set the time, got %v", cc.set)
+	}
+
+	drift, err = camera.SyncTimeFromHost(api.SyncTimeOptionLocation(berlin))(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("SyncTimeFromHost drift %v", drift)
+
+	var sentTime models.TimeInformation
+	var sentDst models.DstInformation
+
+	if err := json.Unmarshal(cc.set["Time"], &sentTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(cc.set["Dst"], &sentDst); err != nil {
+		t.Fatal(err)
+	}
+
+	if sentTime.TimeZone != -3600 || sentTime.TimeFmt != "MM/DD/YYYY" || sentTime.HourFmt != 1 {
+		t.Errorf("expected Berlin's zone and the camera's formats, got %+v", sentTime)
+	}
+
+	if sentDst.Enable != enum.Enabled || sentDst.StartMon != 3 || sentDst.StartWeek != 5 {
+		t.Errorf("expected Berlin's daylight saving rule, got %+v", sentDst)
+	}
+
+	if offset := sentTime.Time(&sentDst).Sub(time.Now()); offset > 2*time.Second || offset < -2*time.Second {
+		t.Errorf("the camera was set %v off the host", offset)
+	}
+}
+
+func TestSystemMixin_SetDeviceTimeZone(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc := &clockCamera{location: time.UTC}
+	registerMockClockCamera(cc)
+
+	// the camera counts the time zone in seconds west of UTC
+	tests := []struct {
+		offset   int
+		timeZone int
+	}{
+		{offset: 2 * 3600, timeZone: -7200},
+		{offset: -5 * 3600, timeZone: 18000},
+	}
+
+	for _, test := range tests {
+		tm := time.Date(2021, time.January, 15, 10, 0, 0, 0, time.FixedZone("", test.offset))
+
+		if _, err := camera.SetDeviceTime(options.WithDeviceTimeOptionTime(tm))(camera.RestHandler); err != nil {
+			t.Fatal(err)
+		}
+
+		var sentTime models.TimeInformation
+
+		if err := json.Unmarshal(cc.set["Time"], &sentTime); err != nil {
+			t.Fatal(err)
+		}
+
+		if sentTime.TimeZone != test.timeZone || sentTime.Hour != 10 {
+			t.Errorf("offset %d sent %+v, expected timeZone %d", test.offset, sentTime, test.timeZone)
+		}
+	}
+}
+
+func TestSystemMixin_SyncTimeFromHostWithoutDst(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	// half an hour of daylight saving cannot be expressed
+	lordHowe := loadLocation(t, "Australia/Lord_Howe")
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc := &clockCamera{location: loadLocation(t, "America/Chicago"), drift: time.Hour}
+	registerMockClockCamera(cc)
+
+	_, err = camera.SyncTimeFromHost(api.SyncTimeOptionLocation(lordHowe))(camera.RestHandler)
+
+	var dstErr *models.DstError
+
+	if !errors.As(err, &dstErr) {
+		t.Fatalf("expected a daylight saving error, got %v", err)
+	}
+
+	if _, ok := cc.set["Dst"]; ok {
+		t.Errorf("no daylight saving rule should be sent, got %s", cc.set["Dst"])
+	}
+
+	var sentTime models.TimeInformation
+
+	if err := json.Unmarshal(cc.set["Time"], &sentTime); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lord Howe's standard time is UTC+10:30
+	if sentTime.TimeZone != -37800 {
+		t.Errorf("expected Lord Howe's zone, got %+v", sentTime)
+	}
+
+	t.Logf("SyncTimeFromHost %v", err)
+}
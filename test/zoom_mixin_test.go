@@ -2,12 +2,16 @@ package test
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/ReolinkCameraAPI/reolinkapigo/internal/pkg/models"
 	"github.com/ReolinkCameraAPI/reolinkapigo/pkg/reolinkapi"
 	"github.com/jarcoal/httpmock"
+	"golang.org/x/net/context"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func registerMockZoomOperation() {
@@ -269,3 +273,216 @@ func TestZoomMixin_StopFocusing(t *testing.T) {
 
 	t.Logf("StopFocusing %v", ok)
 }
+
+// a lens with zoom 0-33 and focus 0-223, StartZoomFocus and SetAutoFocus update it and every command is recorded
+func registerMockZoomFocus(zoomFocus *models.ZoomFocus, autoFocus *models.AutoFocus, commands *[]string) {
+	httpmock.RegisterResponder("POST", "http://127.0.0.1/cgi-bin/api.cgi",
+		func(req *http.Request) (*http.Response, error) {
+
+			type ReqData struct {
+				Cmd    string                     `json:"cmd"`
+				Action int                        `json:"action"`
+				Param  map[string]json.RawMessage `json:"param"`
+			}
+
+			var reqData []*ReqData
+
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			err = json.Unmarshal(data, &reqData)
+
+			if err != nil {
+				return httpmock.NewStringResponse(500, err.Error()), nil
+			}
+
+			setOk := map[string]interface{}{
+				"cmd":  reqData[0].Cmd,
+				"code": 0,
+				"value": map[string]interface{}{
+					"rspCode": 200,
+				},
+			}
+
+			switch reqData[0].Cmd {
+			case "GetZoomFocus":
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetZoomFocus",
+					"code":  0,
+					"value": map[string]interface{}{"ZoomFocus": zoomFocus},
+					"range": map[string]interface{}{
+						"ZoomFocus": models.ZoomFocusRange{
+							Focus: models.ZoomFocusPosRange{Pos: models.MinMax{Min: 0, Max: 223}},
+							Zoom:  models.ZoomFocusPosRange{Pos: models.MinMax{Min: 0, Max: 33}},
+						},
+					},
+				}})
+			case "StartZoomFocus":
+				var op struct {
+					Op  string `json:"op"`
+					Pos int    `json:"pos"`
+				}
+
+				if err := json.Unmarshal(reqData[0].Param["ZoomFocus"], &op); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				if op.Op == "ZoomPos" {
+					zoomFocus.Zoom.Pos = op.Pos
+				} else {
+					zoomFocus.Focus.Pos = op.Pos
+				}
+
+				*commands = append(*commands, fmt.Sprintf("%s %d", op.Op, op.Pos))
+			case "GetAutoFocus":
+				return httpmock.NewJsonResponse(200, []interface{}{map[string]interface{}{
+					"cmd":   "GetAutoFocus",
+					"code":  0,
+					"value": map[string]interface{}{"AutoFocus": autoFocus},
+				}})
+			case "SetAutoFocus":
+				if err := json.Unmarshal(reqData[0].Param["AutoFocus"], autoFocus); err != nil {
+					return httpmock.NewStringResponse(500, err.Error()), nil
+				}
+
+				*commands = append(*commands, fmt.Sprintf("disable %d", autoFocus.Disable))
+			case "SetPtzPreset":
+				*commands = append(*commands, "save preset")
+			case "PtzCtrl":
+				*commands = append(*commands, "goto preset")
+			}
+
+			return httpmock.NewJsonResponse(200, []interface{}{setOk})
+		},
+	)
+}
+
+func TestZoomMixin_ZoomFocusPosition(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	zoomFocus := &models.ZoomFocus{Focus: models.ZoomFocusPos{Pos: 25}, Zoom: models.ZoomFocusPos{Pos: 0}}
+	autoFocus := &models.AutoFocus{Disable: 0}
+	var commands []string
+
+	registerMockZoomFocus(zoomFocus, autoFocus, &commands)
+
+	settings, err := camera.GetZoomFocus()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if settings.ZoomFocus.Focus.Pos != 25 || settings.Range == nil || settings.Range.Zoom.Pos.Max != 33 {
+		t.Errorf("unexpected zoom focus settings %v %v", settings.ZoomFocus, settings.Range)
+	}
+
+	if _, err := camera.SetZoomFocusPosition(10, 120)(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	if zoomFocus.Zoom.Pos != 10 || zoomFocus.Focus.Pos != 120 {
+		t.Errorf("lens did not move, got zoom %d focus %d", zoomFocus.Zoom.Pos, zoomFocus.Focus.Pos)
+	}
+
+	if _, err := camera.SetZoomPosition(40)(camera.RestHandler); err == nil {
+		t.Error("expected a zoom position outside the range to be rejected")
+	}
+
+	if _, err := camera.SetAutoFocus(false)(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	enabled, err := camera.GetAutoFocus()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enabled {
+		t.Error("autofocus should be disabled")
+	}
+
+	if _, err := camera.TriggerAutoFocus()(camera.RestHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	enabled, err = camera.GetAutoFocus()(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !enabled {
+		t.Error("autofocus should be left enabled")
+	}
+
+	// the focus is nudged by a twentieth of its 0-223 range
+	expected := []string{"ZoomPos 10", "FocusPos 120", "disable 1", "disable 1", "FocusPos 131", "disable 0"}
+
+	if fmt.Sprint(commands) != fmt.Sprint(expected) {
+		t.Errorf("expected commands %v, got %v", expected, commands)
+	}
+}
+
+func TestZoomMixin_PresetWithZoomFocus(t *testing.T) {
+	httpmock.Activate()
+
+	defer httpmock.DeactivateAndReset()
+
+	registerMockAuth()
+	camera, err := reolinkapi.NewCamera("127.0.0.1", reolinkapi.WithUsername("foo"), reolinkapi.WithPassword("bar"))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	zoomFocus := &models.ZoomFocus{Focus: models.ZoomFocusPos{Pos: 80}, Zoom: models.ZoomFocusPos{Pos: 12}}
+	var commands []string
+
+	registerMockZoomFocus(zoomFocus, &models.AutoFocus{}, &commands)
+
+	preset, err := camera.SavePresetWithZoomFocus(2, "gate")(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preset.Zoom != 12 || preset.Focus != 80 || preset.Preset.Name != "gate" {
+		t.Errorf("unexpected saved preset %v", preset)
+	}
+
+	zoomFocus.Zoom.Pos = 0
+	zoomFocus.Focus.Pos = 0
+
+	if _, err := camera.GoToPresetWithZoomFocus(context.Background(), nil, 0)(camera.RestHandler); err == nil {
+		t.Error("expected a nil preset to be rejected")
+	}
+
+	_, err = camera.GoToPresetWithZoomFocus(context.Background(), preset, time.Millisecond)(camera.RestHandler)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if zoomFocus.Zoom.Pos != 12 || zoomFocus.Focus.Pos != 80 {
+		t.Errorf("lens position not restored, got zoom %d focus %d", zoomFocus.Zoom.Pos, zoomFocus.Focus.Pos)
+	}
+
+	expected := []string{"save preset", "goto preset", "ZoomPos 12", "FocusPos 80"}
+
+	if fmt.Sprint(commands) != fmt.Sprint(expected) {
+		t.Errorf("expected commands %v, got %v", expected, commands)
+	}
+}